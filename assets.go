@@ -0,0 +1,173 @@
+package bamenn
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // to decode GIF assets
+	_ "image/jpeg" // to decode JPEG assets
+	_ "image/png"  // to decode PNG assets
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AssetDeclarer is an interface for a scene that declares assets it uses.
+// If AssetManager is set to Sequence, the assets are loaded before OnStarter.OnStart and released after OnEnder.OnEnd.
+type AssetDeclarer interface {
+	// Assets returns the paths of assets used by the scene.
+	Assets() []string
+}
+
+// AssetLoader loads an asset from fsys.
+type AssetLoader func(fsys fs.FS, name string) (any, error)
+
+type assetEntry struct {
+	value any
+	refs  int
+}
+
+// AssetManager loads assets from fs.FS and reference-counts them.
+// Assets shared by consecutive scenes are not reloaded when scenes are switched.
+// An asset is disposed when it is no longer referenced. If the asset has Deallocate() or Close() error, it is called.
+type AssetManager struct {
+	fsys    fs.FS
+	loaders map[string]AssetLoader
+	assets  map[string]*assetEntry
+}
+
+// NewAssetManager creates a new AssetManager instance.
+// PNG, JPEG and GIF files are loaded as *ebiten.Image, and other files are loaded as []byte by default.
+func NewAssetManager(fsys fs.FS) *AssetManager {
+	return &AssetManager{
+		fsys: fsys,
+		loaders: map[string]AssetLoader{
+			".png":  LoadImageAsset,
+			".jpg":  LoadImageAsset,
+			".jpeg": LoadImageAsset,
+			".gif":  LoadImageAsset,
+		},
+		assets: make(map[string]*assetEntry),
+	}
+}
+
+// LoadImageAsset is an AssetLoader that loads an image file as *ebiten.Image.
+func LoadImageAsset(fsys fs.FS, name string) (any, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// LoadBytesAsset is an AssetLoader that loads a file as []byte.
+func LoadBytesAsset(fsys fs.FS, name string) (any, error) {
+	return fs.ReadFile(fsys, name)
+}
+
+// SetLoader sets the AssetLoader for the file extension such as ".ogg".
+func (m *AssetManager) SetLoader(ext string, loader AssetLoader) {
+	m.loaders[strings.ToLower(ext)] = loader
+}
+
+// Acquire loads assets that are not loaded yet and increments their reference counts.
+// If an error occurs, the assets acquired by the call are released.
+func (m *AssetManager) Acquire(names []string) error {
+	if m == nil {
+		return nil
+	}
+
+	for i, name := range names {
+		if err := m.acquire(name); err != nil {
+			m.Release(names[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *AssetManager) acquire(name string) error {
+	if e, ok := m.assets[name]; ok {
+		e.refs++
+		return nil
+	}
+
+	loader, ok := m.loaders[strings.ToLower(path.Ext(name))]
+	if !ok {
+		loader = LoadBytesAsset
+	}
+
+	v, err := loader(m.fsys, name)
+	if err != nil {
+		return fmt.Errorf("bamenn: loading asset %q failed: %w", name, err)
+	}
+
+	m.assets[name] = &assetEntry{value: v, refs: 1}
+	return nil
+}
+
+// Release decrements the reference counts of assets and disposes assets that are no longer referenced.
+func (m *AssetManager) Release(names []string) {
+	if m == nil {
+		return
+	}
+
+	for _, name := range names {
+		e, ok := m.assets[name]
+		if !ok {
+			continue
+		}
+		e.refs--
+		if e.refs > 0 {
+			continue
+		}
+		delete(m.assets, name)
+		dispose(e.value)
+	}
+}
+
+func dispose(v any) {
+	switch v := v.(type) {
+	case interface{ Deallocate() }:
+		v.Deallocate()
+	case io.Closer:
+		_ = v.Close()
+	}
+}
+
+// Loaded returns true if the asset is loaded.
+func (m *AssetManager) Loaded(name string) bool {
+	_, ok := m.assets[name]
+	return ok
+}
+
+// Get returns the loaded asset. It returns false if the asset is not loaded.
+func (m *AssetManager) Get(name string) (any, bool) {
+	e, ok := m.assets[name]
+	if !ok {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Image returns the loaded asset as *ebiten.Image. It returns nil if the asset is not loaded or is not an image.
+func (m *AssetManager) Image(name string) *ebiten.Image {
+	v, _ := m.Get(name)
+	img, _ := v.(*ebiten.Image)
+	return img
+}
+
+// Bytes returns the loaded asset as []byte. It returns nil if the asset is not loaded or is not []byte.
+func (m *AssetManager) Bytes(name string) []byte {
+	v, _ := m.Get(name)
+	b, _ := v.([]byte)
+	return b
+}
@@ -0,0 +1,105 @@
+package bamenn_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestSequenceAssets(t *testing.T) {
+	r := recorder{}
+
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("a")},
+		"b.txt": {Data: []byte("b")},
+		"c.txt": {Data: []byte("c")},
+	}
+	m := bamenn.NewAssetManager(fsys)
+	m.SetLoader(".txt", func(fsys fs.FS, name string) (any, error) {
+		r.Append("assets", "load "+name)
+		return &assetForTest{Name: name, Recorder: &r}, nil
+	})
+
+	s1 := assetsDeclarerForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}, assets: []string{"a.txt", "b.txt"}}
+	s2 := assetsDeclarerForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r}}, assets: []string{"b.txt", "c.txt"}}
+
+	seq := bamenn.NewSequence(&s1)
+	seq.SetAssetManager(m)
+
+	s1.UpdateFn = func() error {
+		seq.Switch(&s2)
+		return nil
+	}
+
+	runForTest(t, seq)
+
+	compareLogs(t, []string{
+		"s1:layout",
+		"assets:load a.txt",
+		"assets:load b.txt",
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:update",
+		"s1:ondeparture",
+		"s1:draw",
+		"s1:layout",
+		"s1:onend",
+		"assets:load c.txt",
+		"s2:onstart",
+		"a.txt:dispose",
+		"s2:onarrival",
+		"s2:update",
+	}, r.Log)
+
+	for name, expected := range map[string]bool{"a.txt": false, "b.txt": true, "c.txt": true} {
+		if m.Loaded(name) != expected {
+			t.Errorf("%s: loaded expected %t", name, expected)
+		}
+	}
+}
+
+func TestSequenceAssetsError(t *testing.T) {
+	r := recorder{}
+	fsys := fstest.MapFS{}
+	m := bamenn.NewAssetManager(fsys)
+
+	s1 := assetsDeclarerForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}, assets: []string{"missing.txt"}}
+
+	seq := bamenn.NewSequence(&s1)
+	seq.SetAssetManager(m)
+
+	// The scene is pending until its assets are loaded.
+	for range 2 {
+		if err := seq.Update(); err == nil {
+			t.Errorf("Update() should return the error in loading")
+		}
+	}
+	compareLogs(t, nil, r.Log)
+
+	fsys["missing.txt"] = &fstest.MapFile{Data: []byte("text")}
+	if err := seq.Update(); err != nil {
+		t.Errorf("unexpected err on Update(): %v", err)
+	}
+	compareLogs(t, []string{"s1:onstart", "s1:onarrival", "s1:update"}, r.Log)
+}
+
+type assetsDeclarerForTest struct {
+	eventsForTest
+	assets []string
+}
+
+func (a *assetsDeclarerForTest) Assets() []string {
+	return a.assets
+}
+
+type assetForTest struct {
+	Name     string
+	Recorder *recorder
+}
+
+func (a *assetForTest) Close() error {
+	a.Recorder.Append(a.Name, "dispose")
+	return nil
+}
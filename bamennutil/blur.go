@@ -0,0 +1,138 @@
+package bamennutil
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// maxBlurRadius is the maximum radius of the blur shader in pixels of the shrunk image.
+const maxBlurRadius = 32
+
+// blurShaderRadii are the radii of the blur shader variants. A loop in Kage needs constant bounds,
+// so the variant of the smallest radius covering the blur is used to avoid sampling pixels of zero weight.
+var blurShaderRadii = [...]int{4, 8, 16, maxBlurRadius}
+
+var blurShaders = func() (shaders [len(blurShaderRadii)]*lazyShader) {
+	for i, r := range blurShaderRadii {
+		shaders[i] = newLazyShader(fmt.Sprintf(blurShaderSource, r))
+	}
+	return shaders
+}()
+
+// blurShaderSource is the source of the blur shader. The loop bound is formatted by fmt.Sprintf.
+const blurShaderSource = `//kage:unit pixels
+
+package main
+
+var Direction vec2
+var Radius float
+var Gaussian float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	origin := imageSrc0Origin()
+	size := imageSrc0Size()
+	sigma := max(Radius/2, 0.0001)
+
+	c := vec4(0)
+	total := 0.0
+	for i := -%[1]d; i <= %[1]d; i++ {
+		x := float(i)
+		// The box weight fades at the edge so that the blur grows smoothly with a fractional radius.
+		w := clamp(Radius-abs(x)+1, 0, 1)
+		if Gaussian > 0 {
+			w *= exp(-x*x/(2*sigma*sigma))
+		}
+		p := clamp(srcPos+Direction*x, origin, origin+size-1)
+		c += imageSrc0At(p) * w
+		total += w
+	}
+	return c / total
+}
+`
+
+// blurShader returns the blur shader variant covering the radius.
+func blurShader(radius float64) *lazyShader {
+	for i, r := range blurShaderRadii {
+		if radius <= float64(r) {
+			return blurShaders[i]
+		}
+	}
+	return blurShaders[len(blurShaders)-1]
+}
+
+// BlurKernel is the kernel of BlurDrawer.
+type BlurKernel int
+
+const (
+	// BlurGaussian weights the pixels by the Gaussian function. It is smoother than BlurBox.
+	BlurGaussian BlurKernel = iota
+	// BlurBox weights the pixels equally.
+	BlurBox
+)
+
+// BlurDrawer can be used to draw LinearTransitions. It blurs the screen with the radius growing up to MaxRadius at FrameToSwitch and shrinking afterwards.
+// The radius is eased by the Easing of the LinearTransition.
+type BlurDrawer struct {
+	MaxRadius float64    // MaxRadius is the radius of the blur at FrameToSwitch in pixels. 8 is used if it is not positive.
+	Kernel    BlurKernel // Kernel is the kernel of the blur.
+	Downscale int        // Downscale is the factor to shrink the screen before blurring. A larger value is faster and blurs more widely, but coarser. 1 is used if it is less than 1.
+	Passes    int        // Passes is the number of times to repeat the blur. More passes make the box blur closer to the Gaussian blur. 1 is used if it is less than 1.
+	buffer    gameutil.Buffer
+	small     gameutil.Buffer
+	work      gameutil.Buffer
+}
+
+func (d *BlurDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	downscale := max(d.Downscale, 1)
+	maxRadius := d.MaxRadius
+	if maxRadius <= 0 {
+		maxRadius = 8
+	}
+	// The radius is clipped in the shrunk image. A larger Downscale enables a wider blur.
+	radius := min(maxRadius*switchRate(progress)/float64(downscale), maxBlurRadius)
+	if radius <= 0 {
+		return
+	}
+	gaussian := float32(0)
+	if d.Kernel == BlurGaussian {
+		gaussian = 1
+	}
+
+	size := screen.Bounds().Size()
+	buffer := d.buffer.Capture(screen)
+
+	src := buffer
+	if downscale > 1 {
+		src = d.small.Get(size.X/downscale, size.Y/downscale)
+		drawFitted(src, buffer, ebiten.BlendCopy, 1)
+	}
+	srcSize := src.Bounds().Size()
+	work := d.work.Get(srcSize.X, srcSize.Y)
+
+	shader := blurShader(radius)
+	for range max(d.Passes, 1) {
+		work.Clear()
+		drawShader(work, src, shader, map[string]any{
+			"Direction": []float32{1, 0},
+			"Radius":    float32(radius),
+			"Gaussian":  gaussian,
+		})
+		src.Clear()
+		drawShader(src, work, shader, map[string]any{
+			"Direction": []float32{0, 1},
+			"Radius":    float32(radius),
+			"Gaussian":  gaussian,
+		})
+	}
+
+	origin := screen.Bounds().Min
+	o := ebiten.DrawImageOptions{}
+	o.GeoM.Scale(float64(size.X)/float64(srcSize.X), float64(size.Y)/float64(srcSize.Y))
+	o.GeoM.Translate(float64(origin.X), float64(origin.Y))
+	o.Filter = ebiten.FilterLinear
+	o.Blend = ebiten.BlendCopy
+	screen.DrawImage(src, &o)
+}
@@ -0,0 +1,41 @@
+package bamennutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestBlurDrawer(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Drawer bamennutil.BlurDrawer
+	}{
+		{Name: "gaussian", Drawer: bamennutil.BlurDrawer{MaxRadius: 2}},
+		{Name: "box", Drawer: bamennutil.BlurDrawer{MaxRadius: 2, Kernel: bamennutil.BlurBox, Passes: 2}},
+		{Name: "downscale", Drawer: bamennutil.BlurDrawer{MaxRadius: 4, Downscale: 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			screen := ebiten.NewImage(8, 8)
+			screen.Set(4, 4, color.White)
+
+			c.Drawer.Draw(screen, bamenn.LinearTransitionProgress{CurrentFrame: 10, FrameToSwitch: 5, MaxFrames: 10})
+			if r, _, _, _ := screen.At(3, 4).RGBA(); r != 0 {
+				t.Errorf("the screen must not be blurred at the end, but got %v", screen.At(3, 4))
+			}
+
+			c.Drawer.Draw(screen, bamenn.LinearTransitionProgress{CurrentFrame: 5, FrameToSwitch: 5, MaxFrames: 10})
+			if r, _, _, _ := screen.At(3, 4).RGBA(); r == 0 {
+				t.Errorf("the neighbor must be blurred, but got %v", screen.At(3, 4))
+			}
+			if r, _, _, _ := screen.At(4, 4).RGBA(); r == 0xffff {
+				t.Errorf("the center must be blurred, but got %v", screen.At(4, 4))
+			}
+		})
+	}
+}
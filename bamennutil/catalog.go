@@ -0,0 +1,425 @@
+package bamennutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+// UnmarshalFunc decodes data into v, such as json.Unmarshal or Unmarshal of a YAML package.
+type UnmarshalFunc func(data []byte, v any) error
+
+// TransitionDefinition is a declarative definition of a Transition in a TransitionCatalog.
+//
+// Type is one of the following:
+//
+//   - "none": bamenn.NopTransition.
+//   - "fade": LinearFillFadingDrawer with Color.
+//   - "slide": bamenn.SlideTransition moving to Direction.
+//   - "ripple", "twist", "pagecurl", "flip", "cube" and "shatter": the mesh drawers. "flip" and "cube" rotate to Direction. "shatter" uses Seed.
+//   - "pixelate" and "blur": PixelateDrawer and BlurDrawer.
+//   - "shader": ShaderDrawer with the Kage shader at Shader and Color.
+//   - "composed": ChainTransition of Steps.
+//
+// The drawers are drawn by bamenn.LinearTransition.
+type TransitionDefinition struct {
+	Name      string                 `json:"name" yaml:"name"`           // Name is the name in the catalog. It is ignored in Steps.
+	Type      string                 `json:"type" yaml:"type"`           // Type is the type of the Transition.
+	Frames    int                    `json:"frames" yaml:"frames"`       // Frames is the number of frames of the Transition.
+	Switch    *int                   `json:"switch" yaml:"switch"`       // Switch is the frame to switch scenes. The default is the half of Frames. For "composed", it is the index of the step to switch scenes, and the default is 0.
+	Easing    string                 `json:"easing" yaml:"easing"`       // Easing is the name of the Easing such as "inOutQuad". The default is "linear".
+	Color     string                 `json:"color" yaml:"color"`         // Color is the color in "#rrggbb" or "#rrggbbaa". The default is black.
+	Direction string                 `json:"direction" yaml:"direction"` // Direction is "left", "right", "up" or "down" where the previous scene moves. The default is "left".
+	Shader    string                 `json:"shader" yaml:"shader"`       // Shader is the path of the Kage shader in the fs.FS of the catalog.
+	Seed      uint64                 `json:"seed" yaml:"seed"`           // Seed is the seed of "shatter".
+	Steps     []TransitionDefinition `json:"steps" yaml:"steps"`         // Steps are the Transitions of "composed" in order.
+}
+
+// transitionFile is the root of a file loaded by TransitionCatalog.
+type transitionFile struct {
+	Transitions []TransitionDefinition `json:"transitions" yaml:"transitions"`
+}
+
+// TransitionDefinitionError is an error of an invalid TransitionDefinition.
+type TransitionDefinitionError struct {
+	Index int    // Index is the index of the entry in the file. It is -1 for Define.
+	Name  string // Name is the name of the entry.
+	Field string // Field is the path of the invalid field such as "steps[1].easing".
+	Err   error
+}
+
+func (e *TransitionDefinitionError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("bamennutil: transition %q: %s: %v", e.Name, e.Field, e.Err)
+	}
+	return fmt.Sprintf("bamennutil: transition %q (entry %d): %s: %v", e.Name, e.Index, e.Field, e.Err)
+}
+
+func (e *TransitionDefinitionError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError is an error of a field of a TransitionDefinition.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.field, e.err)
+}
+
+// errorf returns a fieldError of the field in the path.
+func errorf(path, field, format string, args ...any) error {
+	return &fieldError{field: path + field, err: fmt.Errorf(format, args...)}
+}
+
+var easings = map[string]bamenn.Easing{
+	"linear":     bamenn.EaseLinear,
+	"inQuad":     bamenn.EaseInQuad,
+	"outQuad":    bamenn.EaseOutQuad,
+	"inOutQuad":  bamenn.EaseInOutQuad,
+	"inCubic":    bamenn.EaseInCubic,
+	"outCubic":   bamenn.EaseOutCubic,
+	"inOutCubic": bamenn.EaseInOutCubic,
+	"inSine":     bamenn.EaseInSine,
+	"outSine":    bamenn.EaseOutSine,
+	"inOutSine":  bamenn.EaseInOutSine,
+}
+
+// TransitionCatalog is a set of Transitions built from TransitionDefinitions by name.
+// It enables to tune Transitions with files without recompiling.
+type TransitionCatalog struct {
+	fsys    fs.FS
+	defs    map[string]TransitionDefinition
+	names   []string
+	shaders map[string]*ebiten.Shader
+}
+
+// NewTransitionCatalog creates a new TransitionCatalog instance. Files and shaders are read from fsys. fsys can be nil if they are not used.
+func NewTransitionCatalog(fsys fs.FS) *TransitionCatalog {
+	return &TransitionCatalog{
+		fsys:    fsys,
+		defs:    make(map[string]TransitionDefinition),
+		shaders: make(map[string]*ebiten.Shader),
+	}
+}
+
+// Load reads the file from the fs.FS and defines the Transitions in it.
+// The file has "transitions", the list of TransitionDefinitions. If unmarshal is nil, the file is decoded as JSON.
+// Errors of all the invalid entries are joined and returned as TransitionDefinitionErrors. The valid entries are defined.
+func (c *TransitionCatalog) Load(name string, unmarshal UnmarshalFunc) error {
+	if c.fsys == nil {
+		return errors.New("bamennutil: fs.FS of TransitionCatalog is nil")
+	}
+	data, err := fs.ReadFile(c.fsys, name)
+	if err != nil {
+		return err
+	}
+	return c.Parse(data, unmarshal)
+}
+
+// Parse decodes data as Load does.
+func (c *TransitionCatalog) Parse(data []byte, unmarshal UnmarshalFunc) error {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	var f transitionFile
+	if err := unmarshal(data, &f); err != nil {
+		return fmt.Errorf("bamennutil: decoding transitions failed: %w", err)
+	}
+
+	var errs []error
+	for i, def := range f.Transitions {
+		if err := c.define(i, def); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Define validates the TransitionDefinition and adds it. It returns a TransitionDefinitionError if it is invalid.
+func (c *TransitionCatalog) Define(def TransitionDefinition) error {
+	return c.define(-1, def)
+}
+
+func (c *TransitionCatalog) define(index int, def TransitionDefinition) error {
+	var err error
+	switch {
+	case def.Name == "":
+		err = errorf("", "name", "must not be empty")
+	case slices.Contains(c.names, def.Name):
+		err = errorf("", "name", "is duplicated")
+	default:
+		// Building validates the definition and compiles the shaders.
+		_, err = c.build(def, "")
+	}
+	if err != nil {
+		e := &TransitionDefinitionError{Index: index, Name: def.Name, Err: err}
+		if f, ok := err.(*fieldError); ok {
+			e.Field, e.Err = f.field, f.err
+		}
+		return e
+	}
+
+	c.defs[def.Name] = def
+	c.names = append(c.names, def.Name)
+	return nil
+}
+
+// Names returns the names of the Transitions in the order of definition.
+func (c *TransitionCatalog) Names() []string {
+	return slices.Clone(c.names)
+}
+
+// Transition returns a new Transition of the name. A new instance is created at each call, so it can be used with another Transition at the same time.
+func (c *TransitionCatalog) Transition(name string) (bamenn.Transition, error) {
+	def, ok := c.defs[name]
+	if !ok {
+		return nil, fmt.Errorf("bamennutil: unknown transition %q", name)
+	}
+	return c.build(def, "")
+}
+
+// build creates the Transition of def. path is the prefix of the field names in errors.
+func (c *TransitionCatalog) build(def TransitionDefinition, path string) (bamenn.Transition, error) {
+	if err := checkFields(def, path); err != nil {
+		return nil, err
+	}
+
+	switch def.Type {
+	case "none":
+		return bamenn.NopTransition, nil
+	case "composed":
+		return c.buildComposed(def, path)
+	case "slide":
+		return buildSlide(def, path)
+	}
+
+	drawer, err := c.buildDrawer(def, path)
+	if err != nil {
+		return nil, err
+	}
+	if def.Frames <= 0 {
+		return nil, errorf(path, "frames", "must be positive, but %d", def.Frames)
+	}
+	frameToSwitch := def.Frames / 2
+	if def.Switch != nil {
+		frameToSwitch = *def.Switch
+		if frameToSwitch < 0 || frameToSwitch > def.Frames {
+			return nil, errorf(path, "switch", "must be in 0~%d, but %d", def.Frames, frameToSwitch)
+		}
+	}
+	easing, err := parseEasing(def.Easing, path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := bamenn.NewLinearTransition(frameToSwitch, def.Frames, drawer)
+	t.SetEasing(easing)
+	return t, nil
+}
+
+// buildDrawer creates the LinearTransitionDrawer of def.
+func (c *TransitionCatalog) buildDrawer(def TransitionDefinition, path string) (bamenn.LinearTransitionDrawer, error) {
+	switch def.Type {
+	case "fade":
+		clr, err := parseColor(def.Color, path)
+		if err != nil {
+			return nil, err
+		}
+		return LinearFillFadingDrawer{Color: clr}, nil
+	case "ripple":
+		return &RippleDrawer{}, nil
+	case "twist":
+		return &TwistDrawer{}, nil
+	case "pagecurl":
+		return &PageCurlDrawer{}, nil
+	case "flip", "cube":
+		vertical, reverse, err := parseRotation(def.Direction, path)
+		if err != nil {
+			return nil, err
+		}
+		if def.Type == "flip" {
+			return &FlipDrawer{Vertical: vertical, Reverse: reverse}, nil
+		}
+		return &CubeDrawer{Vertical: vertical, Reverse: reverse}, nil
+	case "shatter":
+		return &ShatterDrawer{Seed: def.Seed}, nil
+	case "pixelate":
+		return &PixelateDrawer{}, nil
+	case "blur":
+		return &BlurDrawer{}, nil
+	case "shader":
+		clr, err := parseColor(def.Color, path)
+		if err != nil {
+			return nil, err
+		}
+		shader, err := c.shader(def.Shader, path)
+		if err != nil {
+			return nil, err
+		}
+		return &ShaderDrawer{Shader: shader, Color: clr}, nil
+	case "":
+		return nil, errorf(path, "type", "must not be empty")
+	default:
+		return nil, errorf(path, "type", "unknown type %q", def.Type)
+	}
+}
+
+// buildComposed creates the ChainTransition of def.
+func (c *TransitionCatalog) buildComposed(def TransitionDefinition, path string) (bamenn.Transition, error) {
+	if len(def.Steps) == 0 {
+		return nil, errorf(path, "steps", "must not be empty")
+	}
+	switchStep := 0
+	if def.Switch != nil {
+		switchStep = *def.Switch
+		if switchStep < 0 || switchStep >= len(def.Steps) {
+			return nil, errorf(path, "switch", "must be in 0~%d, but %d", len(def.Steps)-1, switchStep)
+		}
+	}
+
+	steps := make([]bamenn.Transition, len(def.Steps))
+	for i, s := range def.Steps {
+		p := fmt.Sprintf("%ssteps[%d].", path, i)
+		if s.Type == "slide" {
+			return nil, errorf(p, "type", "slide cannot be a step")
+		}
+		t, err := c.build(s, p)
+		if err != nil {
+			return nil, err
+		}
+		steps[i] = t
+	}
+	return NewChainTransition(switchStep, steps...), nil
+}
+
+// buildSlide creates the SlideTransition of def.
+func buildSlide(def TransitionDefinition, path string) (bamenn.Transition, error) {
+	if def.Frames <= 0 {
+		return nil, errorf(path, "frames", "must be positive, but %d", def.Frames)
+	}
+	easing, err := parseEasing(def.Easing, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var edge bamenn.SlideEdge
+	switch def.Direction {
+	case "", "left":
+		edge = bamenn.SlideFromRight
+	case "right":
+		edge = bamenn.SlideFromLeft
+	case "up":
+		edge = bamenn.SlideFromBottom
+	case "down":
+		edge = bamenn.SlideFromTop
+	default:
+		return nil, errorf(path, "direction", "unknown direction %q", def.Direction)
+	}
+
+	t := bamenn.NewSlideTransition(def.Frames, edge)
+	t.SetEasing(easing)
+	return t, nil
+}
+
+// shader returns the compiled shader at the path in the fs.FS.
+func (c *TransitionCatalog) shader(name, path string) (*ebiten.Shader, error) {
+	if name == "" {
+		return nil, errorf(path, "shader", "must not be empty")
+	}
+	if s, ok := c.shaders[name]; ok {
+		return s, nil
+	}
+	if c.fsys == nil {
+		return nil, errorf(path, "shader", "fs.FS of TransitionCatalog is nil")
+	}
+
+	src, err := fs.ReadFile(c.fsys, name)
+	if err != nil {
+		return nil, &fieldError{field: path + "shader", err: err}
+	}
+	s, err := ebiten.NewShader(src)
+	if err != nil {
+		return nil, errorf(path, "shader", "compiling %q failed: %w", name, err)
+	}
+	c.shaders[name] = s
+	return s, nil
+}
+
+// checkFields returns an error if def has a field that its Type does not use.
+func checkFields(def TransitionDefinition, path string) error {
+	uses := func(types ...string) bool { return slices.Contains(types, def.Type) }
+
+	switch {
+	case def.Color != "" && !uses("fade", "shader"):
+		return errorf(path, "color", "is not used by type %q", def.Type)
+	case def.Direction != "" && !uses("slide", "flip", "cube"):
+		return errorf(path, "direction", "is not used by type %q", def.Type)
+	case def.Shader != "" && !uses("shader"):
+		return errorf(path, "shader", "is not used by type %q", def.Type)
+	case def.Seed != 0 && !uses("shatter"):
+		return errorf(path, "seed", "is not used by type %q", def.Type)
+	case len(def.Steps) > 0 && !uses("composed"):
+		return errorf(path, "steps", "is not used by type %q", def.Type)
+	case def.Switch != nil && uses("none", "slide"):
+		return errorf(path, "switch", "is not used by type %q", def.Type)
+	}
+	return nil
+}
+
+// parseEasing returns the Easing of the name.
+func parseEasing(name, path string) (bamenn.Easing, error) {
+	if name == "" {
+		return bamenn.EaseLinear, nil
+	}
+	e, ok := easings[name]
+	if !ok {
+		return nil, errorf(path, "easing", "unknown easing %q", name)
+	}
+	return e, nil
+}
+
+// parseColor parses a color in "#rrggbb" or "#rrggbbaa". An empty string is black.
+func parseColor(s, path string) (color.Color, error) {
+	if s == "" {
+		return color.Black, nil
+	}
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return nil, errorf(path, "color", "must be #rrggbb or #rrggbbaa, but %q", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, errorf(path, "color", "must be #rrggbb or #rrggbbaa, but %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// parseRotation returns the axis and the direction of the rotation to the direction.
+func parseRotation(direction, path string) (vertical, reverse bool, err error) {
+	switch direction {
+	case "", "left":
+		return false, false, nil
+	case "right":
+		return false, true, nil
+	case "up":
+		return true, false, nil
+	case "down":
+		return true, true, nil
+	default:
+		return false, false, errorf(path, "direction", "unknown direction %q", direction)
+	}
+}
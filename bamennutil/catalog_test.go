@@ -0,0 +1,185 @@
+package bamennutil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+const catalogShaderForTest = `//kage:unit pixels
+
+package main
+
+var Rate float
+var Color vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return mix(imageSrc0At(srcPos), Color, Rate)
+}
+`
+
+func TestTransitionCatalogLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"transitions.json": {Data: []byte(`{
+	"transitions": [
+		{"name": "black", "type": "fade", "frames": 30, "switch": 10, "easing": "inOutQuad", "color": "#000000"},
+		{"name": "push", "type": "slide", "frames": 20, "direction": "up"},
+		{"name": "shatter", "type": "shatter", "frames": 40, "seed": 7},
+		{"name": "custom", "type": "shader", "frames": 30, "shader": "fade.kage", "color": "#ff000080"},
+		{"name": "combo", "type": "composed", "switch": 0, "steps": [
+			{"type": "fade", "frames": 10, "switch": 10},
+			{"type": "cube", "frames": 20, "direction": "right"}
+		]},
+		{"name": "cut", "type": "none"}
+	]
+}`)},
+		"fade.kage": {Data: []byte(catalogShaderForTest)},
+	}
+
+	c := bamennutil.NewTransitionCatalog(fsys)
+	if err := c.Load("transitions.json", nil); err != nil {
+		t.Fatalf("unexpected err on Load(): %v", err)
+	}
+
+	expectedNames := []string{"black", "push", "shatter", "custom", "combo", "cut"}
+	names := c.Names()
+	if len(names) != len(expectedNames) {
+		t.Fatalf("expected names %v, but got %v", expectedNames, names)
+	}
+	for i := range expectedNames {
+		if names[i] != expectedNames[i] {
+			t.Errorf("%d: expected %q, but got %q", i, expectedNames[i], names[i])
+		}
+	}
+
+	black, err := c.Transition("black")
+	if err != nil {
+		t.Fatalf("unexpected err on Transition(): %v", err)
+	}
+	linear, ok := black.(*bamenn.LinearTransition)
+	if !ok {
+		t.Fatalf("expected *bamenn.LinearTransition, but got %T", black)
+	}
+	if p := linear.Progress(); p.FrameToSwitch != 10 || p.MaxFrames != 30 || p.Easing == nil {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+
+	another, _ := c.Transition("black")
+	if another == black {
+		t.Errorf("Transition must create a new instance")
+	}
+
+	push, _ := c.Transition("push")
+	if _, ok := push.(*bamenn.SlideTransition); !ok {
+		t.Errorf("expected *bamenn.SlideTransition, but got %T", push)
+	}
+
+	combo, _ := c.Transition("combo")
+	chain, ok := combo.(*bamennutil.ChainTransition)
+	if !ok {
+		t.Fatalf("expected *bamennutil.ChainTransition, but got %T", combo)
+	}
+	if n := len(chain.Steps()); n != 2 {
+		t.Errorf("expected 2 steps, but got %d", n)
+	}
+
+	if cut, _ := c.Transition("cut"); cut != bamenn.NopTransition {
+		t.Errorf("expected bamenn.NopTransition, but got %T", cut)
+	}
+
+	if _, err := c.Transition("unknown"); err == nil {
+		t.Errorf("an unknown name must be an error")
+	}
+}
+
+func TestTransitionCatalogValidation(t *testing.T) {
+	data := []byte(`{
+	"transitions": [
+		{"name": "ok", "type": "fade", "frames": 30},
+		{"name": "typo", "type": "fdae", "frames": 30},
+		{"name": "ok", "type": "fade", "frames": 30},
+		{"name": "nested", "type": "composed", "steps": [
+			{"type": "fade", "frames": 10},
+			{"type": "blur", "frames": 10, "easing": "bounce"}
+		]},
+		{"name": "switch", "type": "fade", "frames": 10, "switch": 11},
+		{"name": "color", "type": "fade", "frames": 10, "color": "red"},
+		{"name": "unused", "type": "ripple", "frames": 10, "direction": "up"},
+		{"name": "noshader", "type": "shader", "frames": 10, "shader": "missing.kage"}
+	]
+}`)
+
+	c := bamennutil.NewTransitionCatalog(fstest.MapFS{})
+	err := c.Parse(data, nil)
+	if err == nil {
+		t.Fatalf("Parse must return an error")
+	}
+
+	expecteds := []struct {
+		Index int
+		Name  string
+		Field string
+	}{
+		{Index: 1, Name: "typo", Field: "type"},
+		{Index: 2, Name: "ok", Field: "name"},
+		{Index: 3, Name: "nested", Field: "steps[1].easing"},
+		{Index: 4, Name: "switch", Field: "switch"},
+		{Index: 5, Name: "color", Field: "color"},
+		{Index: 6, Name: "unused", Field: "direction"},
+		{Index: 7, Name: "noshader", Field: "shader"},
+	}
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != len(expecteds) {
+		t.Fatalf("expected %d errors, but got %d: %v", len(expecteds), len(errs), err)
+	}
+	for i, e := range expecteds {
+		var de *bamennutil.TransitionDefinitionError
+		if !errors.As(errs[i], &de) {
+			t.Errorf("%d: expected TransitionDefinitionError, but got %T", i, errs[i])
+			continue
+		}
+		if de.Index != e.Index || de.Name != e.Name || de.Field != e.Field {
+			t.Errorf("%d: expected %+v, but got %+v (%v)", i, e, *de, de)
+		}
+	}
+
+	// The valid entry is defined.
+	if names := c.Names(); len(names) != 1 || names[0] != "ok" {
+		t.Errorf("expected only \"ok\" to be defined, but got %v", names)
+	}
+}
+
+func TestTransitionCatalogUnmarshalFunc(t *testing.T) {
+	called := false
+	unmarshal := func(data []byte, v any) error {
+		called = true
+		// A YAML package would decode data. This converts a fixed definition for testing.
+		return json.Unmarshal([]byte(`{"transitions": [{"name": "fade", "type": "fade", "frames": 10}]}`), v)
+	}
+
+	c := bamennutil.NewTransitionCatalog(nil)
+	if err := c.Parse([]byte("transitions:\n  - name: fade\n"), unmarshal); err != nil {
+		t.Fatalf("unexpected err on Parse(): %v", err)
+	}
+	if !called {
+		t.Errorf("the UnmarshalFunc must be called")
+	}
+	if _, err := c.Transition("fade"); err != nil {
+		t.Errorf("unexpected err on Transition(): %v", err)
+	}
+}
+
+func TestTransitionCatalogDefine(t *testing.T) {
+	c := bamennutil.NewTransitionCatalog(nil)
+	err := c.Define(bamennutil.TransitionDefinition{Name: "slide", Type: "slide"})
+
+	var de *bamennutil.TransitionDefinitionError
+	if !errors.As(err, &de) || de.Field != "frames" || de.Index != -1 {
+		t.Errorf("expected an error of frames, but got %v", err)
+	}
+}
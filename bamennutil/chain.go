@@ -0,0 +1,131 @@
+package bamennutil
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+// ChainTransition is a bamenn.Transition that processes Transitions one after another, e.g. a fade-out followed by a ripple.
+// Scenes are switched when the step at the index of switchStep can switch scenes.
+// A bamenn.SceneCompositor such as bamenn.SlideTransition does not place scenes as a step.
+type ChainTransition struct {
+	steps      []bamenn.Transition
+	switchStep int
+	current    int
+	canSwitch  bool
+}
+
+// NewChainTransition creates a new ChainTransition instance.
+// If switchStep is out of the range of steps, scenes are switched when the last step is completed.
+func NewChainTransition(switchStep int, steps ...bamenn.Transition) *ChainTransition {
+	return &ChainTransition{steps: steps, switchStep: switchStep}
+}
+
+// Steps returns the steps. The returned slice must not be modified.
+func (t *ChainTransition) Steps() []bamenn.Transition {
+	return t.steps
+}
+
+// Reset is bamenn.Transition implementation. It resets the first step.
+func (t *ChainTransition) Reset() {
+	t.current = 0
+	t.canSwitch = false
+	if len(t.steps) > 0 {
+		t.steps[0].Reset()
+	}
+}
+
+// Update is bamenn.Transition implementation.
+// When the current step is completed, the next step is reset and updated in the same Update, so no frame is spent between steps.
+func (t *ChainTransition) Update() error {
+	for !t.Completed() {
+		s := t.steps[t.current]
+		if err := s.Update(); err != nil {
+			return err
+		}
+		if t.current == t.switchStep && s.CanSwitchScenes() {
+			t.canSwitch = true
+		}
+		if !s.Completed() {
+			return nil
+		}
+
+		if t.current == t.switchStep {
+			t.canSwitch = true
+		}
+		t.current++
+		if t.current < len(t.steps) {
+			t.steps[t.current].Reset()
+		}
+	}
+	return nil
+}
+
+// Draw is bamenn.Transition implementation. It draws the current step.
+func (t *ChainTransition) Draw(screen *ebiten.Image) {
+	if t.Completed() {
+		return
+	}
+	t.steps[t.current].Draw(screen)
+}
+
+// Apply is bamenn.PostEffect implementation.
+// It applies the current step if it implements bamenn.PostEffect, otherwise src is drawn as it is.
+func (t *ChainTransition) Apply(dst, src *ebiten.Image) {
+	if !t.Completed() {
+		if e, ok := t.steps[t.current].(bamenn.PostEffect); ok {
+			e.Apply(dst, src)
+			return
+		}
+	}
+	dst.DrawImage(src, nil)
+}
+
+// SetFreezeFrame is bamenn.FreezeFrameSetter implementation. It passes the image to the steps implementing bamenn.FreezeFrameSetter.
+func (t *ChainTransition) SetFreezeFrame(img *ebiten.Image) {
+	for _, s := range t.steps {
+		if f, ok := s.(bamenn.FreezeFrameSetter); ok {
+			f.SetFreezeFrame(img)
+		}
+	}
+}
+
+// HoldsSceneUpdate is bamenn.SceneUpdateHolder implementation. It returns true while the current step holds back Update of the scene.
+func (t *ChainTransition) HoldsSceneUpdate() bool {
+	if t.Completed() {
+		return false
+	}
+	h, ok := t.steps[t.current].(bamenn.SceneUpdateHolder)
+	return ok && h.HoldsSceneUpdate()
+}
+
+// HandleInput is bamenn.InputHandler implementation. It routes the input to the current step if it implements bamenn.InputHandler.
+func (t *ChainTransition) HandleInput(input *bamenn.Input) {
+	if t.Completed() {
+		return
+	}
+	if h, ok := t.steps[t.current].(bamenn.InputHandler); ok {
+		h.HandleInput(input)
+	}
+}
+
+// OnEnd is bamenn.OnEnder implementation. It is called when bamenn.Sequence ends during the Transition,
+// and calls OnEnd of the current step if it implements bamenn.OnEnder.
+func (t *ChainTransition) OnEnd() {
+	if t.Completed() {
+		return
+	}
+	if o, ok := t.steps[t.current].(bamenn.OnEnder); ok {
+		o.OnEnd()
+	}
+}
+
+// Completed is bamenn.Transition implementation. It returns true after all the steps are completed.
+func (t *ChainTransition) Completed() bool {
+	return t.current >= len(t.steps)
+}
+
+// CanSwitchScenes is bamenn.Transition implementation.
+func (t *ChainTransition) CanSwitchScenes() bool {
+	return t.canSwitch
+}
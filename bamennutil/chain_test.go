@@ -0,0 +1,113 @@
+package bamennutil_test
+
+import (
+	"testing"
+
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestChainTransition(t *testing.T) {
+	drawer := bamennutil.LinearFillFadingDrawer{}
+	first := bamenn.NewLinearTransition(1, 2, drawer)
+	second := bamenn.NewLinearTransition(2, 3, drawer)
+	chain := bamennutil.NewChainTransition(1, first, second)
+
+	chain.Reset()
+	type state struct{ canSwitch, completed bool }
+	var states []state
+	for range 6 {
+		if err := chain.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		states = append(states, state{chain.CanSwitchScenes(), chain.Completed()})
+	}
+
+	expecteds := []state{
+		{false, false}, // first: 1
+		{false, false}, // first: 2, completed, second: 1
+		{true, false},  // second: 2, switch
+		{true, true},   // second: 3, completed
+		{true, true},
+		{true, true},
+	}
+	for i, e := range expecteds {
+		if states[i] != e {
+			t.Errorf("%d: expected %+v, but got %+v", i, e, states[i])
+		}
+	}
+
+	// Reset restarts the first step.
+	chain.Reset()
+	if chain.CanSwitchScenes() || chain.Completed() {
+		t.Errorf("the chain must be restarted by Reset")
+	}
+	if p := first.Progress(); p.CurrentFrame != 0 {
+		t.Errorf("the first step must be reset, but the current frame is %d", p.CurrentFrame)
+	}
+}
+
+func TestChainTransitionFrames(t *testing.T) {
+	drawer := bamennutil.LinearFillFadingDrawer{}
+	chain := bamennutil.NewChainTransition(1, bamenn.NewLinearTransition(1, 2, drawer), bamenn.NopTransition, bamenn.NewLinearTransition(2, 3, drawer))
+	chain.Reset()
+
+	// The next step is updated in the Update that completes the current step, and NopTransition takes no Update.
+	frames := 0
+	for !chain.Completed() && frames < 100 {
+		if err := chain.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		frames++
+	}
+	if frames != 4 {
+		t.Errorf("expected 4 Updates, but got %d", frames)
+	}
+}
+
+func TestChainTransitionHoldsSceneUpdate(t *testing.T) {
+	card := &cardForTest{}
+	card.updateFn = func() error {
+		card.frames++
+		return nil
+	}
+	chain := bamennutil.NewChainTransition(0, bamenn.NewInterstitialTransition(card), bamenn.NewLinearTransition(1, 2, bamennutil.LinearFillFadingDrawer{}))
+	chain.Reset()
+
+	var holds []bool
+	for range 3 {
+		if err := chain.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		holds = append(holds, chain.HoldsSceneUpdate())
+	}
+
+	// The card is completed at the second Update, and the next step does not hold the scene.
+	expected := []bool{true, false, false}
+	for i := range expected {
+		if holds[i] != expected[i] {
+			t.Errorf("%d: expected %t, but got %t", i, expected[i], holds[i])
+		}
+	}
+}
+
+type cardForTest struct {
+	dummyScene
+	frames int
+}
+
+func (c *cardForTest) Completed() bool {
+	return c.frames >= 2
+}
+
+func TestChainTransitionSwitchAtCompletion(t *testing.T) {
+	chain := bamennutil.NewChainTransition(0, bamenn.NopTransition, bamenn.NewLinearTransition(0, 2, bamennutil.LinearFillFadingDrawer{}))
+	chain.Reset()
+	chain.Update()
+	if !chain.CanSwitchScenes() {
+		t.Errorf("scenes must be switched when the switch step is completed")
+	}
+	if chain.Completed() {
+		t.Errorf("the chain must not be completed")
+	}
+}
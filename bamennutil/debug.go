@@ -0,0 +1,191 @@
+package bamennutil
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/noppikinatta/bamenn"
+)
+
+// DebugOverlay wraps an ebiten.Game such as bamenn.Sequence or bamenn.Parallel and draws its state over the screen.
+// It draws the current scenes, the Transition phase, frame counters, the Games of bamenn.Parallel with their Layout sizes and recent events.
+// It is toggled with ToggleKey.
+type DebugOverlay struct {
+	gameWrapper
+	ToggleKey ebiten.Key // ToggleKey is the key to toggle visibility. The default is F1.
+	MaxLogs   int        // MaxLogs is the maximum number of event logs to keep. The default is 10.
+	visible   bool
+	updates   int
+	draws     int
+	logs      []string
+	observed  map[observable]bool
+}
+
+// NewDebugOverlay creates a new DebugOverlay instance. It is hidden at first.
+func NewDebugOverlay(game ebiten.Game) *DebugOverlay {
+	return &DebugOverlay{
+		gameWrapper: gameWrapper{game: game},
+		ToggleKey:   ebiten.KeyF1,
+		MaxLogs:     10,
+	}
+}
+
+// SetVisible sets the visibility of it.
+func (d *DebugOverlay) SetVisible(visible bool) {
+	d.visible = visible
+}
+
+// Visible returns true if it is visible.
+func (d *DebugOverlay) Visible() bool {
+	return d.visible
+}
+
+// Logs returns recent event logs in chronological order.
+func (d *DebugOverlay) Logs() []string {
+	return d.logs
+}
+
+// Update is ebiten.Game implementation.
+func (d *DebugOverlay) Update() error {
+	if inpututil.IsKeyJustPressed(d.ToggleKey) {
+		d.visible = !d.visible
+	}
+
+	d.observe(d.game)
+	err := d.game.Update()
+	d.updates++
+	return err
+}
+
+// Draw is ebiten.Game implementation.
+func (d *DebugOverlay) Draw(screen *ebiten.Image) {
+	d.game.Draw(screen)
+	d.draws++
+
+	if d.visible {
+		ebitenutil.DebugPrint(screen, d.String())
+	}
+}
+
+// String returns the text drawn by it.
+func (d *DebugOverlay) String() string {
+	b := strings.Builder{}
+
+	fmt.Fprintf(&b, "frame: update %d / draw %d\n", d.updates, d.draws)
+	d.describe(&b, d.game, "")
+
+	b.WriteString("events:\n")
+	for _, l := range d.logs {
+		fmt.Fprintf(&b, "  %s\n", l)
+	}
+
+	return b.String()
+}
+
+// observable is implemented by bamenn.Sequence and bamenn.Parallel.
+type observable interface {
+	EventObserver() bamenn.EventObserver
+	SetEventObserver(observer bamenn.EventObserver)
+}
+
+// sequenceLike is implemented by bamenn.Sequence and types embedding it such as bamenn.Flow.
+type sequenceLike interface {
+	observable
+	Current() ebiten.Game
+	Transition() bamenn.Transition
+	TransitionPhase() bamenn.TransitionPhase
+}
+
+// parallelLike is implemented by bamenn.Parallel.
+type parallelLike interface {
+	observable
+	Games() []ebiten.Game
+	LayoutSize(game ebiten.Game) (bamenn.LayoutSize, bool)
+}
+
+func (d *DebugOverlay) describe(b *strings.Builder, g ebiten.Game, indent string) {
+	switch g := g.(type) {
+	case sequenceLike:
+		fmt.Fprintf(b, "%ssequence: %T\n", indent, g.Current())
+		if t := g.Transition(); t != nil {
+			fmt.Fprintf(b, "%s  transition: %T %s%s\n", indent, t, g.TransitionPhase(), transitionProgress(t))
+		}
+		d.describe(b, g.Current(), indent+"  ")
+	case parallelLike:
+		fmt.Fprintf(b, "%sparallel:\n", indent)
+		for i, c := range g.Games() {
+			// The size at the last Layout is shown, because Layout may have side effects.
+			size := "?"
+			if s, ok := g.LayoutSize(c); ok {
+				size = fmt.Sprintf("%gx%g", math.Ceil(s.Width), math.Ceil(s.Height))
+			}
+			fmt.Fprintf(b, "%s  [%d] %T %s\n", indent, i, c, size)
+			d.describe(b, c, indent+"    ")
+		}
+	case bamenn.Wrapper:
+		d.describe(b, g.Unwrap(), indent)
+	}
+}
+
+// transitionProgress returns the progress of t if it is available.
+func transitionProgress(t bamenn.Transition) string {
+	p, ok := t.(interface {
+		Progress() bamenn.LinearTransitionProgress
+	})
+	if !ok {
+		return ""
+	}
+	pr := p.Progress()
+	return fmt.Sprintf(" %d/%d (switch at %d)", pr.CurrentFrame, pr.MaxFrames, pr.FrameToSwitch)
+}
+
+// observe hooks the EventObserver of g and its descendants recursively.
+func (d *DebugOverlay) observe(g ebiten.Game) {
+	switch g := g.(type) {
+	case sequenceLike:
+		d.hook(g)
+		d.observe(g.Current())
+	case parallelLike:
+		d.hook(g)
+		for _, c := range g.Games() {
+			d.observe(c)
+		}
+	case bamenn.Wrapper:
+		d.observe(g.Unwrap())
+	}
+}
+
+// hook chains appendLog after the EventObserver of o. It is done only once for each o.
+func (d *DebugOverlay) hook(o observable) {
+	if d.observed[o] {
+		return
+	}
+	if d.observed == nil {
+		d.observed = map[observable]bool{}
+	}
+	d.observed[o] = true
+
+	prev := o.EventObserver()
+	o.SetEventObserver(func(scene ebiten.Game, event bamenn.Event) {
+		if prev != nil {
+			prev(scene, event)
+		}
+		d.appendLog(scene, event)
+	})
+}
+
+func (d *DebugOverlay) appendLog(scene ebiten.Game, event bamenn.Event) {
+	if event == bamenn.EventStart {
+		// Observe nested scenes before they start.
+		d.observe(scene)
+	}
+
+	d.logs = append(d.logs, fmt.Sprintf("%d %s %T", d.updates, event, scene))
+	if over := len(d.logs) - d.MaxLogs; over > 0 {
+		d.logs = d.logs[over:]
+	}
+}
@@ -0,0 +1,80 @@
+package bamennutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestDebugOverlay(t *testing.T) {
+	s1 := dummyScene{}
+	s21 := dummyScene{}
+	s22 := dummyScene{}
+
+	seq := bamenn.NewSequence(&s1)
+	seq2 := bamenn.NewSequence(bamenn.NewParallel(&s21, &s22))
+
+	s1.updateFn = func() error {
+		seq.Switch(seq2)
+		return nil
+	}
+
+	// The EventObserver set by the user is kept.
+	userEvents := 0
+	seq.SetEventObserver(func(scene ebiten.Game, event bamenn.Event) {
+		userEvents++
+	})
+
+	overlay := bamennutil.NewDebugOverlay(seq)
+	overlay.MaxLogs = 11
+	for range 3 {
+		overlay.Layout(4, 3)
+		if err := overlay.Update(); err != nil {
+			t.Fatalf("unexpected err on Game.Update(): %v", err)
+		}
+	}
+
+	expectedLogs := []string{
+		"0 OnArrival *bamennutil_test.dummyScene",
+		"0 OnDeparture *bamennutil_test.dummyScene",
+		"1 OnEnd *bamennutil_test.dummyScene",
+		"1 OnStart *bamenn.Sequence",
+		"1 OnStart *bamenn.Parallel",
+		"1 OnStart *bamennutil_test.dummyScene",
+		"1 OnStart *bamennutil_test.dummyScene",
+		"1 OnArrival *bamenn.Sequence",
+		"1 OnArrival *bamenn.Parallel",
+		"1 OnArrival *bamennutil_test.dummyScene",
+		"1 OnArrival *bamennutil_test.dummyScene",
+	}
+
+	logs := overlay.Logs()
+	if len(logs) != len(expectedLogs) {
+		t.Fatalf("expected %d logs, but got %d: %v", len(expectedLogs), len(logs), logs)
+	}
+	for i := range expectedLogs {
+		if logs[i] != expectedLogs[i] {
+			t.Errorf("%d: log different\nex: %s\nac: %s", i, expectedLogs[i], logs[i])
+		}
+	}
+
+	if userEvents != 6 {
+		t.Errorf("EventObserver of the user should be called 6 times, but got %d", userEvents)
+	}
+
+	text := overlay.String()
+	for _, expected := range []string{
+		"frame: update 3 / draw 0",
+		"sequence: *bamenn.Sequence",
+		"  sequence: *bamenn.Parallel",
+		"    parallel:",
+		"      [1] *bamennutil_test.dummyScene 4x3",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("text should contain %q:\n%s", expected, text)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package bamennutil
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+// The drawers in this file can be used to draw LinearTransitions.
+// They deform the image of the previous scene with DrawTriangles by the EasedRate of bamenn.LinearTransitionProgress.
+// The image is the freeze frame of bamenn.Sequence if it is enabled, otherwise the screen captured until scenes are switched.
+// Until scenes are switched, the deformed image is drawn on the cleared screen. After that, it is drawn over the next scene.
+// A FrameToSwitch of 1 shows the next scene behind the deformation from the beginning.
+
+// defaultMeshSegments is used if Segments is less than 1.
+const defaultMeshSegments = 32
+
+// meshSegments returns segments or defaultMeshSegments.
+func meshSegments(segments int) int {
+	if segments < 1 {
+		return defaultMeshSegments
+	}
+	return segments
+}
+
+// orDefault returns v if it is positive, otherwise d.
+func orDefault(v, d float64) float64 {
+	if v > 0 {
+		return v
+	}
+	return d
+}
+
+// RippleDrawer ripples the previous scene from the center and fades it out.
+type RippleDrawer struct {
+	freezeFrame
+	Amplitude  float64 // Amplitude is the maximum displacement in pixels. 16 is used if it is not positive.
+	Wavelength float64 // Wavelength is the wavelength in pixels. 64 is used if it is not positive.
+	Waves      float64 // Waves is the number of waves emitted during the transition. 3 is used if it is not positive.
+	Segments   int     // Segments is the number of divisions of the mesh. 32 is used if it is less than 1. It is up to 128.
+	mesh       mesh
+}
+
+func (d *RippleDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	t := progress.EasedRate()
+	amplitude := orDefault(d.Amplitude, 16) * math.Sin(math.Pi*min(t*2, 1))
+	wavelength := orDefault(d.Wavelength, 64)
+	waves := orDefault(d.Waves, 3)
+	alpha := float32(1 - t)
+
+	size := screen.Bounds().Size()
+	cx, cy := float64(size.X)/2, float64(size.Y)/2
+	d.mesh.grid(src, float32(size.X), float32(size.Y), meshSegments(d.Segments), func(x, y float32) (float32, float32, float32, float32) {
+		vx, vy := float64(x)-cx, float64(y)-cy
+		r := math.Hypot(vx, vy)
+		if r == 0 {
+			return x, y, 1, alpha
+		}
+		off := amplitude * math.Sin(2*math.Pi*(r/wavelength-t*waves))
+		return float32(float64(x) + vx/r*off), float32(float64(y) + vy/r*off), 1, alpha
+	})
+	d.mesh.draw(screen, src)
+}
+
+// TwistDrawer twists the previous scene into the center like a vortex.
+type TwistDrawer struct {
+	freezeFrame
+	Angle    float64 // Angle is the rotation at the center at the end of the transition in radians. 2π is used if it is 0.
+	Segments int     // Segments is the number of divisions of the mesh. 32 is used if it is less than 1. It is up to 128.
+	mesh     mesh
+}
+
+func (d *TwistDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	t := progress.EasedRate()
+	angle := d.Angle
+	if angle == 0 {
+		angle = 2 * math.Pi
+	}
+	scale := 1 - t
+
+	size := screen.Bounds().Size()
+	cx, cy := float64(size.X)/2, float64(size.Y)/2
+	radius := math.Hypot(cx, cy)
+	d.mesh.grid(src, float32(size.X), float32(size.Y), meshSegments(d.Segments), func(x, y float32) (float32, float32, float32, float32) {
+		vx, vy := float64(x)-cx, float64(y)-cy
+		w := 1 - math.Hypot(vx, vy)/radius
+		sin, cos := math.Sincos(angle * t * w * w)
+		dx := cx + (vx*cos-vy*sin)*scale
+		dy := cy + (vx*sin+vy*cos)*scale
+		return float32(dx), float32(dy), 1, 1
+	})
+	d.mesh.draw(screen, src)
+}
+
+// PageCurlDrawer curls the previous scene from the right edge like turning a page.
+// The back of the page is drawn darker.
+type PageCurlDrawer struct {
+	freezeFrame
+	Radius   float64 // Radius is the radius of the curl in pixels. 1/8 of the screen width is used if it is not positive.
+	Shade    float64 // Shade is the brightness of the back of the page in the range of 0.0~1.0. 0.6 is used if it is not positive.
+	Segments int     // Segments is the number of divisions of the mesh. 32 is used if it is less than 1. It is up to 128.
+	mesh     mesh
+}
+
+func (d *PageCurlDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	t := progress.EasedRate()
+	size := screen.Bounds().Size()
+	width := float64(size.X)
+	radius := orDefault(d.Radius, width/8)
+	back := min(orDefault(d.Shade, 0.6), 1)
+	// The curl line moves from the right edge until the whole page is curled out of the screen.
+	line := width - t*(width+radius)
+
+	d.mesh.grid(src, float32(size.X), float32(size.Y), meshSegments(d.Segments), func(x, y float32) (float32, float32, float32, float32) {
+		dist := float64(x) - line
+		switch {
+		case dist <= 0:
+			return x, y, 1, 1
+		case dist < math.Pi*radius:
+			theta := dist / radius
+			shade := 1 - (1-back)*theta/math.Pi
+			return float32(line + radius*math.Sin(theta)), y, float32(shade), 1
+		default:
+			return float32(line - (dist - math.Pi*radius)), y, float32(back), 1
+		}
+	})
+	d.mesh.draw(screen, src)
+}
+
+// rotation is a rotation of a plane in 3D space projected with perspective.
+type rotation struct {
+	vertical    bool
+	angle       float64
+	pivot       float64 // pivot is the distance from the plane to the rotation axis behind it.
+	perspective float64
+}
+
+// project returns the projected position of the point of the plane and its brightness.
+func (r rotation) project(x, y, width, height float64) (float32, float32, float32) {
+	cx, cy := width/2, height/2
+	u, v := x-cx, y-cy
+	if r.vertical {
+		u, v = v, u
+	}
+
+	sin, cos := math.Sincos(r.angle)
+	pu := u*cos - r.pivot*sin
+	depth := r.pivot - (u*sin + r.pivot*cos)
+	distance := max(width, height) / r.perspective
+	s := distance / (distance + depth)
+	pu, pv := pu*s, v*s
+
+	if r.vertical {
+		pu, pv = pv, pu
+	}
+	return float32(cx + pu), float32(cy + pv), float32(max(cos, 0))
+}
+
+// FlipDrawer flips the previous scene around the center axis like a card until it is edge-on.
+// Its left side goes back, or its top side if Vertical is true.
+type FlipDrawer struct {
+	freezeFrame
+	Vertical    bool    // Vertical flips around the horizontal axis.
+	Reverse     bool    // Reverse flips in the opposite direction.
+	Perspective float64 // Perspective is the strength of the perspective. 0.5 is used if it is not positive.
+	Segments    int     // Segments is the number of divisions of the mesh. 32 is used if it is less than 1. It is up to 128.
+	mesh        mesh
+}
+
+func (d *FlipDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	r := rotation{
+		vertical:    d.Vertical,
+		angle:       rotationAngle(progress, d.Reverse),
+		perspective: orDefault(d.Perspective, 0.5),
+	}
+	drawRotation(&d.mesh, screen, src, r, d.Segments)
+}
+
+// CubeDrawer rotates the previous scene away as a face of a cube.
+// It rotates to the left, or to the top if Vertical is true.
+type CubeDrawer struct {
+	freezeFrame
+	Vertical    bool    // Vertical rotates around the horizontal axis.
+	Reverse     bool    // Reverse rotates to the right, or to the bottom if Vertical is true.
+	Perspective float64 // Perspective is the strength of the perspective. 0.5 is used if it is not positive.
+	Segments    int     // Segments is the number of divisions of the mesh. 32 is used if it is less than 1. It is up to 128.
+	mesh        mesh
+}
+
+func (d *CubeDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	pivot := float64(size.X) / 2
+	if d.Vertical {
+		pivot = float64(size.Y) / 2
+	}
+	r := rotation{
+		vertical:    d.Vertical,
+		angle:       rotationAngle(progress, d.Reverse),
+		pivot:       pivot,
+		perspective: orDefault(d.Perspective, 0.5),
+	}
+	drawRotation(&d.mesh, screen, src, r, d.Segments)
+}
+
+// rotationAngle returns the angle of the rotation up to 90 degrees.
+func rotationAngle(progress bamenn.LinearTransitionProgress, reverse bool) float64 {
+	angle := progress.EasedRate() * math.Pi / 2
+	if reverse {
+		return -angle
+	}
+	return angle
+}
+
+// drawRotation draws src rotated by r onto screen.
+func drawRotation(m *mesh, screen, src *ebiten.Image, r rotation, segments int) {
+	size := screen.Bounds().Size()
+	width, height := float64(size.X), float64(size.Y)
+	m.grid(src, float32(width), float32(height), meshSegments(segments), func(x, y float32) (float32, float32, float32, float32) {
+		dx, dy, shade := r.project(float64(x), float64(y), width, height)
+		return dx, dy, shade, 1
+	})
+	m.draw(screen, src)
+}
+
+// maxShardDivisions is the maximum number of columns and rows of ShatterDrawer.
+const maxShardDivisions = 64
+
+// shard is a triangle piece of ShatterDrawer.
+type shard struct {
+	points [3][2]float64 // points are the vertices relative to the centroid in the range of 0.0~1.0.
+	cx, cy float64       // cx and cy are the centroid in the range of 0.0~1.0.
+	vx, vy float64       // vx and vy are the initial velocity in the ratio of the Force.
+	spin   float64       // spin is the angular velocity in radians per frame.
+}
+
+// ShatterDrawer shatters the previous scene into triangles thrown away from the center and falling by gravity.
+// The pieces are decided by Seed, so the same Seed always gives the same result.
+type ShatterDrawer struct {
+	freezeFrame
+	Seed    uint64  // Seed is the seed of the random shapes and motions of the pieces.
+	Columns int     // Columns is the number of columns of the pieces. 8 is used if it is less than 1. It is up to 64.
+	Rows    int     // Rows is the number of rows of the pieces. 6 is used if it is less than 1. It is up to 64.
+	Force   float64 // Force is the initial speed of the pieces in pixels per frame. 4 is used if it is not positive.
+	Gravity float64 // Gravity is the acceleration downward in pixels per frame squared. 0.5 is used if it is 0.
+	shards  []shard
+	key     [3]uint64
+	mesh    mesh
+}
+
+func (d *ShatterDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	src := d.source(screen, progress)
+	if src == nil {
+		return
+	}
+
+	d.prepareShards()
+
+	size := screen.Bounds().Size()
+	width, height := float64(size.X), float64(size.Y)
+	srcSize := src.Bounds().Size()
+	t := progress.EasedRate()
+	frames := t * float64(progress.MaxFrames)
+	force := orDefault(d.Force, 4)
+	gravity := d.Gravity
+	if gravity == 0 {
+		gravity = 0.5
+	}
+	alpha := float32(1 - t*t)
+
+	d.mesh.reset()
+	for _, s := range d.shards {
+		px := s.cx*width + s.vx*force*frames
+		py := s.cy*height + s.vy*force*frames + gravity*frames*frames/2
+		sin, cos := math.Sincos(s.spin * frames)
+		for _, p := range s.points {
+			ox, oy := p[0]*width, p[1]*height
+			d.mesh.indices = append(d.mesh.indices, d.mesh.add(
+				float32((s.cx+p[0])*float64(srcSize.X)),
+				float32((s.cy+p[1])*float64(srcSize.Y)),
+				float32(px+ox*cos-oy*sin),
+				float32(py+ox*sin+oy*cos),
+				1,
+				alpha,
+			))
+		}
+	}
+	d.mesh.draw(screen, src)
+}
+
+// prepareShards builds the shards if the settings are changed.
+func (d *ShatterDrawer) prepareShards() {
+	cols := d.Columns
+	if cols < 1 {
+		cols = 8
+	}
+	rows := d.Rows
+	if rows < 1 {
+		rows = 6
+	}
+	// The number of vertices must fit in uint16.
+	cols, rows = min(cols, maxShardDivisions), min(rows, maxShardDivisions)
+	key := [3]uint64{d.Seed, uint64(cols), uint64(rows)}
+	if d.shards != nil && d.key == key {
+		return
+	}
+	d.key = key
+
+	rnd := rand.New(rand.NewPCG(d.Seed, d.Seed^0x9e3779b97f4a7c15))
+
+	// The inner points of the grid are jittered to make irregular triangles.
+	points := make([][2]float64, (cols+1)*(rows+1))
+	for j := range rows + 1 {
+		for i := range cols + 1 {
+			x, y := float64(i)/float64(cols), float64(j)/float64(rows)
+			if i > 0 && i < cols {
+				x += (rnd.Float64() - 0.5) * 0.6 / float64(cols)
+			}
+			if j > 0 && j < rows {
+				y += (rnd.Float64() - 0.5) * 0.6 / float64(rows)
+			}
+			points[j*(cols+1)+i] = [2]float64{x, y}
+		}
+	}
+
+	d.shards = make([]shard, 0, cols*rows*2)
+	for j := range rows {
+		for i := range cols {
+			p00 := points[j*(cols+1)+i]
+			p10 := points[j*(cols+1)+i+1]
+			p01 := points[(j+1)*(cols+1)+i]
+			p11 := points[(j+1)*(cols+1)+i+1]
+			tris := [2][3][2]float64{{p00, p10, p01}, {p10, p11, p01}}
+			if rnd.IntN(2) == 0 {
+				tris = [2][3][2]float64{{p00, p10, p11}, {p00, p11, p01}}
+			}
+			for _, tri := range tris {
+				d.shards = append(d.shards, newShard(tri, rnd))
+			}
+		}
+	}
+}
+
+// newShard creates a shard of the triangle moving away from the center.
+func newShard(tri [3][2]float64, rnd *rand.Rand) shard {
+	s := shard{
+		cx: (tri[0][0] + tri[1][0] + tri[2][0]) / 3,
+		cy: (tri[0][1] + tri[1][1] + tri[2][1]) / 3,
+	}
+	for i, p := range tri {
+		s.points[i] = [2]float64{p[0] - s.cx, p[1] - s.cy}
+	}
+
+	dx, dy := s.cx-0.5, s.cy-0.5
+	if l := math.Hypot(dx, dy); l > 0 {
+		dx, dy = dx/l, dy/l
+	}
+	speed := 0.5 + rnd.Float64()
+	s.vx = dx * speed
+	s.vy = dy*speed - rnd.Float64()
+	s.spin = (rnd.Float64() - 0.5) * 0.2
+	return s
+}
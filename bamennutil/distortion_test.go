@@ -0,0 +1,93 @@
+package bamennutil_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+type meshDrawer interface {
+	bamenn.LinearTransitionDrawer
+	bamenn.FreezeFrameSetter
+}
+
+func TestMeshDrawers(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	cases := []struct {
+		Name   string
+		Drawer func() meshDrawer
+	}{
+		{Name: "ripple", Drawer: func() meshDrawer { return &bamennutil.RippleDrawer{} }},
+		{Name: "twist", Drawer: func() meshDrawer { return &bamennutil.TwistDrawer{} }},
+		{Name: "pagecurl", Drawer: func() meshDrawer { return &bamennutil.PageCurlDrawer{} }},
+		{Name: "flip", Drawer: func() meshDrawer { return &bamennutil.FlipDrawer{} }},
+		{Name: "cube", Drawer: func() meshDrawer { return &bamennutil.CubeDrawer{Vertical: true} }},
+		{Name: "shatter", Drawer: func() meshDrawer { return &bamennutil.ShatterDrawer{Seed: 1} }},
+	}
+
+	for _, c := range cases {
+		for _, freeze := range []bool{false, true} {
+			name := c.Name
+			if freeze {
+				name += "-freeze"
+			}
+			t.Run(name, func(t *testing.T) {
+				s1 := dummyScene{drawFn: func(screen *ebiten.Image) { screen.Fill(red) }}
+				s2 := dummyScene{drawFn: func(screen *ebiten.Image) { screen.Fill(blue) }}
+
+				game := bamenn.NewSequence(&s1)
+				game.SetFreezeFrame(freeze)
+				game.Layout(16, 16)
+				tran := bamenn.NewLinearTransition(1, 10, c.Drawer())
+
+				screen := ebiten.NewImage(16, 16)
+				game.Update()
+				game.SwitchWithTransition(&s2, tran)
+
+				game.Draw(screen)
+				if got := screen.At(8, 8); got != red {
+					t.Errorf("first frame: expected %v, but got %v", red, got)
+				}
+
+				for range 11 {
+					if err := game.Update(); err != nil {
+						t.Fatalf("unexpected err on Game.Update(): %v", err)
+					}
+					game.Draw(screen)
+				}
+				if got := screen.At(8, 8); got != blue {
+					t.Errorf("last frame: expected %v, but got %v", blue, got)
+				}
+			})
+		}
+	}
+}
+
+func TestShatterDrawerSeed(t *testing.T) {
+	draw := func(seed uint64) []byte {
+		src := ebiten.NewImage(16, 16)
+		src.Fill(color.White)
+		screen := ebiten.NewImage(16, 16)
+
+		d := bamennutil.ShatterDrawer{Seed: seed, Columns: 2, Rows: 2}
+		d.SetFreezeFrame(src)
+		d.Draw(screen, bamenn.LinearTransitionProgress{CurrentFrame: 5, MaxFrames: 10, FrameToSwitch: 1})
+
+		pixels := make([]byte, 4*16*16)
+		screen.ReadPixels(pixels)
+		return pixels
+	}
+
+	if !bytes.Equal(draw(1), draw(1)) {
+		t.Errorf("the same seed must give the same result")
+	}
+	if bytes.Equal(draw(1), draw(2)) {
+		t.Errorf("different seeds should give different results")
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 // LinearFillFadingDrawer can be used to draw LinearTransitions. It performs a fade-in/fade-out that fills in the specified color.
+// The alpha of the color is eased by the Easing of the LinearTransition.
 type LinearFillFadingDrawer struct {
 	Color color.Color
 }
@@ -17,7 +18,7 @@ func (d LinearFillFadingDrawer) Draw(screen *ebiten.Image, progress bamenn.Linea
 
 	o := ebiten.DrawImageOptions{}
 	o.ColorScale.ScaleWithColor(d.Color)
-	o.ColorScale.ScaleAlpha(float32(d.alpha(progress)))
+	o.ColorScale.ScaleAlpha(float32(switchRate(progress)))
 	o.GeoM.Scale(
 		float64(screenSize.X),
 		float64(screenSize.Y),
@@ -26,17 +27,18 @@ func (d LinearFillFadingDrawer) Draw(screen *ebiten.Image, progress bamenn.Linea
 	screen.DrawImage(dummyWhitePixel, &o)
 }
 
-func (d LinearFillFadingDrawer) alpha(progress bamenn.LinearTransitionProgress) float64 {
-	alpha := 0.0
+// switchRate returns the rate that increases to 1 at FrameToSwitch and decreases to 0 at MaxFrames, eased by the Easing.
+func switchRate(progress bamenn.LinearTransitionProgress) float64 {
+	rate := 0.0
 
 	switch f := progress.CurrentFrame - progress.FrameToSwitch; {
 	case f < 0:
-		alpha = float64(progress.CurrentFrame+1) / float64(progress.FrameToSwitch+1)
+		rate = float64(progress.CurrentFrame+1) / float64(progress.FrameToSwitch+1)
 	case f == 0:
-		alpha = 1
+		rate = 1
 	case f > 0:
-		alpha = float64(progress.MaxFrames-progress.CurrentFrame) / float64(progress.MaxFrames-progress.FrameToSwitch)
+		rate = float64(progress.MaxFrames-progress.CurrentFrame) / float64(progress.MaxFrames-progress.FrameToSwitch)
 	}
 
-	return alpha
+	return progress.Easing.Apply(rate)
 }
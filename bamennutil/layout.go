@@ -0,0 +1,111 @@
+package bamennutil
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+type screenLayoutMode int
+
+const (
+	screenLayoutFixed screenLayoutMode = iota
+	screenLayoutStretch
+	screenLayoutPixelPerfect
+	screenLayoutExpand
+)
+
+// ScreenLayout wraps an ebiten.Game and implements Layout, LayoutF and DrawFinalScreen with a virtual resolution.
+// Layout and LayoutF of the wrapped ebiten.Game are not called. Other interfaces are handled by the wrapped ebiten.Game through bamenn.Wrapper.
+type ScreenLayout struct {
+	gameWrapper
+	mode         screenLayoutMode
+	width        float64
+	height       float64
+	screenWidth  float64
+	screenHeight float64
+}
+
+// NewFixedScreenLayout returns a ScreenLayout with the fixed virtual resolution.
+// The screen is scaled keeping the aspect ratio and letterboxed like Ebitengine does. Use NewStretchScreenLayout to fill the window.
+func NewFixedScreenLayout(game ebiten.Game, width, height int) *ScreenLayout {
+	return newScreenLayout(game, screenLayoutFixed, width, height)
+}
+
+// NewStretchScreenLayout returns a ScreenLayout with the fixed virtual resolution. The screen is stretched to fill the window ignoring the aspect ratio.
+func NewStretchScreenLayout(game ebiten.Game, width, height int) *ScreenLayout {
+	return newScreenLayout(game, screenLayoutStretch, width, height)
+}
+
+// NewPixelPerfectScreenLayout returns a ScreenLayout with the fixed virtual resolution. The screen is scaled by an integer factor.
+func NewPixelPerfectScreenLayout(game ebiten.Game, width, height int) *ScreenLayout {
+	return newScreenLayout(game, screenLayoutPixelPerfect, width, height)
+}
+
+// NewExpandScreenLayout returns a ScreenLayout that expands the virtual resolution to fill the window.
+// The specified width and height are the safe area, which is always visible in the center of the screen.
+func NewExpandScreenLayout(game ebiten.Game, width, height int) *ScreenLayout {
+	return newScreenLayout(game, screenLayoutExpand, width, height)
+}
+
+func newScreenLayout(game ebiten.Game, mode screenLayoutMode, width, height int) *ScreenLayout {
+	return &ScreenLayout{
+		gameWrapper:  gameWrapper{game: game},
+		mode:         mode,
+		width:        float64(width),
+		height:       float64(height),
+		screenWidth:  float64(width),
+		screenHeight: float64(height),
+	}
+}
+
+// Layout is ebiten.Game implementation.
+func (l *ScreenLayout) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	w, h := l.LayoutF(float64(outsideWidth), float64(outsideHeight))
+	return int(math.Ceil(w)), int(math.Ceil(h))
+}
+
+// LayoutF is ebiten.LayoutFer implementation.
+func (l *ScreenLayout) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	l.screenWidth, l.screenHeight = l.width, l.height
+
+	if l.mode == screenLayoutExpand && outsideWidth > 0 && outsideHeight > 0 {
+		s := math.Min(outsideWidth/l.width, outsideHeight/l.height)
+		l.screenWidth, l.screenHeight = outsideWidth/s, outsideHeight/s
+	}
+
+	return l.screenWidth, l.screenHeight
+}
+
+// SafeArea returns the area of the virtual resolution specified at creation in the last laid out screen.
+func (l *ScreenLayout) SafeArea() image.Rectangle {
+	x := int(math.Floor((l.screenWidth - l.width) / 2))
+	y := int(math.Floor((l.screenHeight - l.height) / 2))
+	return image.Rect(x, y, x+int(l.width), y+int(l.height))
+}
+
+// DrawFinalScreen is ebiten.FinalScreenDrawer implementation.
+// It forwards geoM modified for the layout to the wrapped ebiten.Game if it implements ebiten.FinalScreenDrawer.
+func (l *ScreenLayout) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	if p, ok := l.scalingPolicy(); ok {
+		ob := offscreen.Bounds()
+		sb := screen.Bounds()
+		geoM = p.GeoM(float64(ob.Dx()), float64(ob.Dy()), float64(sb.Dx()), float64(sb.Dy()))
+	}
+
+	drawFinalScreen(l.game, screen, offscreen, geoM)
+}
+
+// scalingPolicy returns the bamenn.ScalingPolicy to draw the final screen. If ok is false, geoM of Ebitengine is used.
+func (l *ScreenLayout) scalingPolicy() (policy bamenn.ScalingPolicy, ok bool) {
+	switch l.mode {
+	case screenLayoutStretch:
+		return bamenn.ScalingStretch, true
+	case screenLayoutPixelPerfect:
+		return bamenn.ScalingInteger, true
+	default:
+		return bamenn.ScalingNone, false
+	}
+}
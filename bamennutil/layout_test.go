@@ -0,0 +1,201 @@
+package bamennutil_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestScreenLayoutLayoutF(t *testing.T) {
+	cases := []struct {
+		Name             string
+		Layout           *bamennutil.ScreenLayout
+		OutsideW         float64
+		OutsideH         float64
+		ExpectedW        float64
+		ExpectedH        float64
+		ExpectedSafeArea image.Rectangle
+	}{
+		{
+			Name:             "fixed",
+			Layout:           bamennutil.NewFixedScreenLayout(&dummyScene{}, 320, 240),
+			OutsideW:         1000,
+			OutsideH:         500,
+			ExpectedW:        320,
+			ExpectedH:        240,
+			ExpectedSafeArea: image.Rect(0, 0, 320, 240),
+		},
+		{
+			Name:             "expand-horizontal",
+			Layout:           bamennutil.NewExpandScreenLayout(&dummyScene{}, 320, 240),
+			OutsideW:         1280,
+			OutsideH:         480,
+			ExpectedW:        640,
+			ExpectedH:        240,
+			ExpectedSafeArea: image.Rect(160, 0, 480, 240),
+		},
+		{
+			Name:             "expand-vertical",
+			Layout:           bamennutil.NewExpandScreenLayout(&dummyScene{}, 320, 240),
+			OutsideW:         640,
+			OutsideH:         960,
+			ExpectedW:        320,
+			ExpectedH:        480,
+			ExpectedSafeArea: image.Rect(0, 120, 320, 360),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w, h := c.Layout.LayoutF(c.OutsideW, c.OutsideH)
+			if w != c.ExpectedW || h != c.ExpectedH {
+				t.Errorf("expected (%f,%f), but got (%f,%f)", c.ExpectedW, c.ExpectedH, w, h)
+			}
+
+			if a := c.Layout.SafeArea(); a != c.ExpectedSafeArea {
+				t.Errorf("safe area expected %v, but got %v", c.ExpectedSafeArea, a)
+			}
+		})
+	}
+}
+
+func TestScreenLayoutDrawFinalScreen(t *testing.T) {
+	cases := []struct {
+		Name         string
+		LayoutFn     func(g ebiten.Game) *bamennutil.ScreenLayout
+		ExpectedMinX float64
+		ExpectedMinY float64
+		ExpectedMaxX float64
+		ExpectedMaxY float64
+	}{
+		{
+			Name:         "fixed",
+			LayoutFn:     func(g ebiten.Game) *bamennutil.ScreenLayout { return bamennutil.NewFixedScreenLayout(g, 10, 10) },
+			ExpectedMinX: 1,
+			ExpectedMinY: 2,
+			ExpectedMaxX: 11,
+			ExpectedMaxY: 12,
+		},
+		{
+			Name:         "stretch",
+			LayoutFn:     func(g ebiten.Game) *bamennutil.ScreenLayout { return bamennutil.NewStretchScreenLayout(g, 10, 10) },
+			ExpectedMinX: 0,
+			ExpectedMinY: 0,
+			ExpectedMaxX: 35,
+			ExpectedMaxY: 25,
+		},
+		{
+			Name:         "pixel-perfect",
+			LayoutFn:     func(g ebiten.Game) *bamennutil.ScreenLayout { return bamennutil.NewPixelPerfectScreenLayout(g, 10, 10) },
+			ExpectedMinX: 7,
+			ExpectedMinY: 2,
+			ExpectedMaxX: 27,
+			ExpectedMaxY: 22,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var actual ebiten.GeoM
+			g := &finalScreenDrawerScene{drawFn: func(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+				actual = geoM
+			}}
+
+			given := ebiten.GeoM{}
+			given.Translate(1, 2)
+
+			l := c.LayoutFn(g)
+			l.DrawFinalScreen(ebiten.NewImage(35, 25), ebiten.NewImage(10, 10), given)
+
+			minX, minY := actual.Apply(0, 0)
+			maxX, maxY := actual.Apply(10, 10)
+
+			if minX != c.ExpectedMinX || minY != c.ExpectedMinY {
+				t.Errorf("min expected (%f,%f), but got (%f,%f)", c.ExpectedMinX, c.ExpectedMinY, minX, minY)
+			}
+			if maxX != c.ExpectedMaxX || maxY != c.ExpectedMaxY {
+				t.Errorf("max expected (%f,%f), but got (%f,%f)", c.ExpectedMaxX, c.ExpectedMaxY, maxX, maxY)
+			}
+		})
+	}
+}
+
+type finalScreenDrawerScene struct {
+	dummyScene
+	drawFn func(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM)
+}
+
+func (s *finalScreenDrawerScene) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	s.drawFn(screen, offscreen, geoM)
+}
+
+func TestScreenLayoutForwarding(t *testing.T) {
+	s := forwardedScene{completed: true}
+	l := bamennutil.NewFixedScreenLayout(&s, 10, 10)
+
+	if _, ok := any(l).(bamenn.Completer); ok {
+		t.Errorf("ScreenLayout should not implement bamenn.Completer by itself")
+	}
+
+	cache := bamenn.NewSceneCache(1)
+	cache.Register("layout", func() ebiten.Game { return l })
+	cache.Register("other", func() ebiten.Game { return &dummyScene{} })
+
+	var completed ebiten.Game
+	seq := bamenn.NewSequence(&dummyScene{})
+	seq.SetSceneCache(cache)
+	seq.SetOnComplete(func(child ebiten.Game) { completed = child })
+
+	if _, err := seq.SwitchByKey("layout", bamenn.NopTransition); err != nil {
+		t.Fatalf("unexpected err on SwitchByKey(): %v", err)
+	}
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if completed != ebiten.Game(l) {
+		t.Errorf("Completed() of the wrapped scene should be used")
+	}
+
+	if _, err := seq.SwitchByKey("other", bamenn.NopTransition); err != nil {
+		t.Fatalf("unexpected err on SwitchByKey(): %v", err)
+	}
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if !s.disposed {
+		t.Errorf("OnDispose() of the wrapped scene should be called")
+	}
+}
+
+func TestScreenLayoutInParallel(t *testing.T) {
+	s := forwardedScene{}
+	p := bamenn.NewParallel(bamennutil.NewFixedScreenLayout(&s, 320, 240), &dummyScene{})
+
+	p.LayoutF(640, 480)
+	if s.width != 640 || s.height != 480 {
+		t.Errorf("SetScreenSize() should be forwarded with (640, 480), but got (%f, %f)", s.width, s.height)
+	}
+}
+
+type forwardedScene struct {
+	dummyScene
+	completed bool
+	disposed  bool
+	width     float64
+	height    float64
+}
+
+func (s *forwardedScene) Completed() bool {
+	return s.completed
+}
+
+func (s *forwardedScene) OnDispose() {
+	s.disposed = true
+}
+
+func (s *forwardedScene) SetScreenSize(width, height float64) {
+	s.width, s.height = width, height
+}
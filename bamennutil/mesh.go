@@ -0,0 +1,120 @@
+package bamennutil
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// freezeFrame holds the image of the previous scene deformed by mesh drawers.
+type freezeFrame struct {
+	image    *ebiten.Image
+	captured gameutil.Buffer
+	valid    bool
+	frame    int
+}
+
+// SetFreezeFrame is bamenn.FreezeFrameSetter implementation.
+// The image is set by bamenn.Sequence when its freeze frame is enabled.
+func (f *freezeFrame) SetFreezeFrame(img *ebiten.Image) {
+	f.image = img
+}
+
+// source returns the image of the previous scene to deform. It returns nil if there is no image.
+// Without the freeze frame of bamenn.Sequence, the screen is captured until scenes are switched.
+// The screen is cleared until scenes are switched, because it shows the previous scene.
+func (f *freezeFrame) source(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) *ebiten.Image {
+	if progress.CurrentFrame < f.frame {
+		f.valid = false
+	}
+	f.frame = progress.CurrentFrame
+	beforeSwitch := progress.CurrentFrame == 0 || progress.CurrentFrame < progress.FrameToSwitch
+
+	img := f.image
+	if img == nil {
+		if beforeSwitch {
+			f.captured.Capture(screen)
+			f.valid = true
+		}
+		if f.valid {
+			img = f.captured.Image
+		}
+	}
+
+	if beforeSwitch {
+		screen.Clear()
+	}
+	return img
+}
+
+// maxMeshSegments is the maximum number of segments so that vertex indices fit in uint16.
+const maxMeshSegments = 128
+
+// mesh is a reusable buffer of vertices and indices for DrawTriangles.
+type mesh struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// reset clears the vertices and the indices.
+func (m *mesh) reset() {
+	m.vertices = m.vertices[:0]
+	m.indices = m.indices[:0]
+}
+
+// add adds a vertex and returns its index. The color is premultiplied by alpha.
+func (m *mesh) add(srcX, srcY, dstX, dstY, shade, alpha float32) uint16 {
+	m.vertices = append(m.vertices, ebiten.Vertex{
+		SrcX:   srcX,
+		SrcY:   srcY,
+		DstX:   dstX,
+		DstY:   dstY,
+		ColorR: shade * alpha,
+		ColorG: shade * alpha,
+		ColorB: shade * alpha,
+		ColorA: alpha,
+	})
+	return uint16(len(m.vertices) - 1)
+}
+
+// grid builds a grid of segments x segments cells mapping src onto a destination of width x height.
+// fn transforms a destination position to the deformed position, the brightness and the alpha.
+// Cells are added from left to right and from top to bottom, so the later cells are drawn over the earlier cells.
+func (m *mesh) grid(src *ebiten.Image, width, height float32, segments int, fn func(x, y float32) (dx, dy, shade, alpha float32)) {
+	m.reset()
+	segments = min(max(segments, 1), maxMeshSegments)
+	srcSize := src.Bounds().Size()
+
+	for j := 0; j <= segments; j++ {
+		for i := 0; i <= segments; i++ {
+			u := float32(i) / float32(segments)
+			v := float32(j) / float32(segments)
+			dx, dy, shade, alpha := fn(u*width, v*height)
+			m.add(u*float32(srcSize.X), v*float32(srcSize.Y), dx, dy, shade, alpha)
+		}
+	}
+
+	stride := uint16(segments + 1)
+	for j := range uint16(segments) {
+		for i := range uint16(segments) {
+			v := j*stride + i
+			m.indices = append(m.indices, v, v+1, v+stride, v+1, v+stride+1, v+stride)
+		}
+	}
+}
+
+// draw draws the triangles of src onto dst. The vertices are translated by the origins of the images.
+func (m *mesh) draw(dst, src *ebiten.Image) {
+	origin := dst.Bounds().Min
+	srcOrigin := src.Bounds().Min
+	for i := range m.vertices {
+		m.vertices[i].DstX += float32(origin.X)
+		m.vertices[i].DstY += float32(origin.Y)
+		m.vertices[i].SrcX += float32(srcOrigin.X)
+		m.vertices[i].SrcY += float32(srcOrigin.Y)
+	}
+
+	o := ebiten.DrawTrianglesOptions{}
+	o.Filter = ebiten.FilterLinear
+	dst.DrawTriangles(m.vertices, m.indices, src, &o)
+}
@@ -0,0 +1,76 @@
+package bamennutil
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+var pixelateShader = newLazyShader(`//kage:unit pixels
+
+package main
+
+var BlockSize float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	origin := imageSrc0Origin()
+	size := imageSrc0Size()
+	block := origin + floor((srcPos-origin)/BlockSize)*BlockSize
+
+	// 4x4 samples are averaged in each block.
+	c := vec4(0)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			p := block + (vec2(float(i), float(j))+0.5)/4*BlockSize
+			c += imageSrc0At(clamp(p, origin, origin+size-1))
+		}
+	}
+	return c / 16
+}
+`)
+
+// PixelateDrawer can be used to draw LinearTransitions. It pixelates the screen into blocks growing up to MaxBlockSize at FrameToSwitch and shrinking afterwards.
+// The block size is eased by the Easing of the LinearTransition.
+type PixelateDrawer struct {
+	MaxBlockSize int  // MaxBlockSize is the size of the blocks at FrameToSwitch in pixels. 16 is used if it is less than 2.
+	Average      bool // Average averages 4x4 samples in each block with a shader. It is slower, but reduces flickering of the blocks.
+	buffer       gameutil.Buffer
+	small        gameutil.Buffer
+}
+
+func (d *PixelateDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	maxBlockSize := d.MaxBlockSize
+	if maxBlockSize < 2 {
+		maxBlockSize = 16
+	}
+	block := int(math.Round(1 + float64(maxBlockSize-1)*switchRate(progress)))
+	if block < 2 {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	buffer := d.buffer.Capture(screen)
+
+	if d.Average {
+		drawShader(screen, buffer, pixelateShader, map[string]any{
+			"BlockSize": float32(block),
+		})
+		return
+	}
+
+	// A pixel of each block is sampled by shrinking the screen, and the blocks are drawn by enlarging it.
+	w, h := (size.X+block-1)/block, (size.Y+block-1)/block
+	small := d.small.Get(w, h)
+	o := ebiten.DrawImageOptions{}
+	o.GeoM.Scale(1/float64(block), 1/float64(block))
+	small.DrawImage(buffer, &o)
+
+	origin := screen.Bounds().Min
+	o = ebiten.DrawImageOptions{}
+	o.GeoM.Scale(float64(block), float64(block))
+	o.GeoM.Translate(float64(origin.X), float64(origin.Y))
+	o.Blend = ebiten.BlendCopy
+	screen.DrawImage(small, &o)
+}
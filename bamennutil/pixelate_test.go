@@ -0,0 +1,32 @@
+package bamennutil_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestPixelateDrawer(t *testing.T) {
+	for _, average := range []bool{false, true} {
+		screen := ebiten.NewImage(4, 4)
+		screen.SubImage(image.Rect(0, 0, 2, 4)).(*ebiten.Image).Fill(color.White)
+
+		d := bamennutil.PixelateDrawer{MaxBlockSize: 4, Average: average}
+
+		// The screen is not changed at the end.
+		d.Draw(screen, bamenn.LinearTransitionProgress{CurrentFrame: 10, FrameToSwitch: 5, MaxFrames: 10})
+		if l, r := screen.At(0, 0), screen.At(3, 0); l == r {
+			t.Errorf("average=%t: the screen must not be pixelated at the end, but got %v", average, l)
+		}
+
+		// The whole screen is a block at FrameToSwitch.
+		d.Draw(screen, bamenn.LinearTransitionProgress{CurrentFrame: 5, FrameToSwitch: 5, MaxFrames: 10})
+		if l, r := screen.At(0, 0), screen.At(3, 3); l != r {
+			t.Errorf("average=%t: the screen must be a block, but got %v and %v", average, l, r)
+		}
+	}
+}
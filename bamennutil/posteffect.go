@@ -0,0 +1,234 @@
+package bamennutil
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// ScanlinesEffect is a bamenn.PostEffect that darkens horizontal lines like a CRT display.
+type ScanlinesEffect struct {
+	Interval  int     // Interval is the distance between lines in pixels. 2 is used if it is less than 2.
+	Intensity float64 // Intensity is the darkness of lines in the range of 0.0~1.0.
+}
+
+// Apply is bamenn.PostEffect implementation.
+func (e ScanlinesEffect) Apply(dst, src *ebiten.Image) {
+	dst.DrawImage(src, nil)
+
+	interval := e.Interval
+	if interval < 2 {
+		interval = 2
+	}
+
+	size := dst.Bounds().Size()
+	for y := 0; y < size.Y; y += interval {
+		o := ebiten.DrawImageOptions{}
+		o.ColorScale.Scale(0, 0, 0, float32(e.Intensity))
+		o.GeoM.Scale(float64(size.X), 1)
+		o.GeoM.Translate(0, float64(y))
+		dst.DrawImage(dummyWhitePixel, &o)
+	}
+}
+
+var vignetteShader = newLazyShader(`//kage:unit pixels
+
+package main
+
+var Color vec4
+var Intensity float
+var Radius float
+var Softness float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	p := (srcPos-imageSrc0Origin())/imageSrc0Size() - 0.5
+	d := length(p) * 2
+	v := smoothstep(Radius, Radius+max(Softness, 0.0001), d) * Intensity
+	return mix(c, Color, clamp(v, 0, 1))
+}
+`)
+
+// VignetteEffect is a bamenn.PostEffect that darkens the edges of the screen.
+type VignetteEffect struct {
+	Color     color.Color // Color is the color of the edges. Black is used if it is nil.
+	Intensity float64     // Intensity is the strength of the effect in the range of 0.0~1.0.
+	Radius    float64     // Radius is the distance from the center where the effect begins. 1.0 is the middle of an edge.
+	Softness  float64     // Softness is the width of the gradient.
+}
+
+// Apply is bamenn.PostEffect implementation.
+func (e VignetteEffect) Apply(dst, src *ebiten.Image) {
+	drawShader(dst, src, vignetteShader, map[string]any{
+		"Color":     colorUniform(e.Color),
+		"Intensity": float32(e.Intensity),
+		"Radius":    float32(e.Radius),
+		"Softness":  float32(e.Softness),
+	})
+}
+
+var colorGradingShader = newLazyShader(`//kage:unit pixels
+
+package main
+
+var Size float
+var Intensity float
+
+func lut(slice float, rg vec2) vec3 {
+	p := rg * (Size - 1)
+	p0 := floor(p)
+	p1 := min(p0+1, vec2(Size-1))
+	f := p - p0
+	base := imageSrc1Origin() + vec2(slice*Size, 0) + 0.5
+	c00 := imageSrc1At(base + p0).rgb
+	c10 := imageSrc1At(base + vec2(p1.x, p0.y)).rgb
+	c01 := imageSrc1At(base + vec2(p0.x, p1.y)).rgb
+	c11 := imageSrc1At(base + p1).rgb
+	return mix(mix(c00, c10, f.x), mix(c01, c11, f.x), f.y)
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	if c.a == 0 {
+		return c
+	}
+	rgb := clamp(c.rgb/c.a, 0, 1)
+	b := rgb.b * (Size - 1)
+	b0 := floor(b)
+	b1 := min(b0+1, Size-1)
+	graded := mix(lut(b0, rgb.rg), lut(b1, rgb.rg), b-b0)
+	return vec4(mix(rgb, graded, Intensity)*c.a, c.a)
+}
+`)
+
+// ColorGradingEffect is a bamenn.PostEffect that maps colors with a lookup table.
+// The lookup table is an image of N*N x N pixels, which has N slices of blue arranged horizontally.
+// In each slice, red increases to the right and green increases downward.
+// The lookup table can be larger than the screen.
+type ColorGradingEffect struct {
+	lut       *ebiten.Image
+	size      int
+	canvas    gameutil.Buffer
+	source    gameutil.Buffer
+	Intensity float64 // Intensity is the strength of the effect in the range of 0.0~1.0.
+}
+
+// NewColorGradingEffect returns a new ColorGradingEffect with the lookup table.
+func NewColorGradingEffect(lut *ebiten.Image) *ColorGradingEffect {
+	return &ColorGradingEffect{
+		lut:       lut,
+		size:      lut.Bounds().Dy(),
+		Intensity: 1,
+	}
+}
+
+// Apply is bamenn.PostEffect implementation.
+// If the lookup table has less than 2 slices, src is drawn as it is.
+func (e *ColorGradingEffect) Apply(dst, src *ebiten.Image) {
+	if e.size < 2 {
+		dst.DrawImage(src, nil)
+		return
+	}
+
+	// All the source images of a shader must be the same size.
+	// Both src and the lookup table are placed on canvases large enough for each of them, and the extra area is clipped by dst.
+	srcSize := src.Bounds().Size()
+	lutSize := e.lut.Bounds().Size()
+	w, h := max(srcSize.X, lutSize.X), max(srcSize.Y, lutSize.Y)
+
+	if img := e.canvas.Image; img == nil || img.Bounds().Dx() != w || img.Bounds().Dy() != h {
+		canvas := e.canvas.Get(w, h)
+		canvas.DrawImage(e.lut, nil)
+	}
+	if srcSize.X != w || srcSize.Y != h {
+		source := e.source.Get(w, h)
+		source.DrawImage(src, nil)
+		src = source
+	}
+
+	origin := dst.Bounds().Min
+	op := &ebiten.DrawRectShaderOptions{}
+	op.GeoM.Translate(float64(origin.X), float64(origin.Y))
+	op.Images[0] = src
+	op.Images[1] = e.canvas.Image
+	op.Uniforms = map[string]any{
+		"Size":      float32(e.size),
+		"Intensity": float32(e.Intensity),
+	}
+	dst.DrawRectShader(w, h, colorGradingShader.get(), op)
+}
+
+var brightPassShader = newLazyShader(`//kage:unit pixels
+
+package main
+
+var Threshold float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	l := dot(c.rgb, vec3(0.2126, 0.7152, 0.0722))
+	return c * clamp((l-Threshold)/max(1-Threshold, 0.0001), 0, 1)
+}
+`)
+
+// BloomEffect is a bamenn.PostEffect that makes bright areas glow.
+type BloomEffect struct {
+	Threshold float64 // Threshold is the luminance where the glow begins in the range of 0.0~1.0.
+	Intensity float64 // Intensity is the strength of the glow.
+	Downscale int     // Downscale is the factor to shrink the glow. A larger value spreads the glow more. 4 is used if it is less than 2.
+
+	bright  gameutil.Buffer
+	small   gameutil.Buffer
+	smaller gameutil.Buffer
+}
+
+// Apply is bamenn.PostEffect implementation.
+func (e *BloomEffect) Apply(dst, src *ebiten.Image) {
+	dst.DrawImage(src, nil)
+
+	downscale := e.Downscale
+	if downscale < 2 {
+		downscale = 4
+	}
+
+	size := src.Bounds().Size()
+	bright := e.bright.Get(size.X, size.Y)
+	drawShader(bright, src, brightPassShader, map[string]any{
+		"Threshold": float32(e.Threshold),
+	})
+
+	// Blur by shrinking twice and enlarging with the linear filter.
+	small := e.small.Get(size.X/downscale, size.Y/downscale)
+	drawFitted(small, bright, ebiten.BlendSourceOver, 1)
+	smaller := e.smaller.Get(size.X/downscale/2, size.Y/downscale/2)
+	drawFitted(smaller, small, ebiten.BlendSourceOver, 1)
+
+	drawFitted(dst, smaller, ebiten.BlendLighter, float32(e.Intensity))
+}
+
+// drawFitted draws src onto dst scaled to fill dst with the linear filter.
+func drawFitted(dst, src *ebiten.Image, blend ebiten.Blend, scale float32) {
+	ds := dst.Bounds().Size()
+	ss := src.Bounds().Size()
+
+	o := ebiten.DrawImageOptions{}
+	o.GeoM.Scale(float64(ds.X)/float64(ss.X), float64(ds.Y)/float64(ss.Y))
+	o.Filter = ebiten.FilterLinear
+	o.Blend = blend
+	o.ColorScale.Scale(scale, scale, scale, scale)
+	dst.DrawImage(src, &o)
+}
+
+// ScreenShakeEffect is a bamenn.PostEffect that shifts the screen.
+type ScreenShakeEffect struct {
+	OffsetX float64 // OffsetX is the horizontal shift in pixels.
+	OffsetY float64 // OffsetY is the vertical shift in pixels.
+}
+
+// Apply is bamenn.PostEffect implementation.
+func (e ScreenShakeEffect) Apply(dst, src *ebiten.Image) {
+	o := ebiten.DrawImageOptions{}
+	o.GeoM.Translate(e.OffsetX, e.OffsetY)
+	dst.DrawImage(src, &o)
+}
@@ -0,0 +1,81 @@
+package bamennutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestPostEffects(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Effect   interface{ Apply(dst, src *ebiten.Image) }
+		X, Y     int
+		Expected color.Color
+	}{
+		{
+			Name:     "scanlines-line",
+			Effect:   bamennutil.ScanlinesEffect{Interval: 2, Intensity: 1},
+			X:        1,
+			Y:        0,
+			Expected: color.RGBA{0, 0, 0, 255},
+		},
+		{
+			Name:     "scanlines-between-lines",
+			Effect:   bamennutil.ScanlinesEffect{Interval: 2, Intensity: 1},
+			X:        1,
+			Y:        1,
+			Expected: color.RGBA{255, 255, 255, 255},
+		},
+		{
+			Name:     "screenshake-shifted-out",
+			Effect:   bamennutil.ScreenShakeEffect{OffsetX: 1},
+			X:        0,
+			Y:        1,
+			Expected: color.RGBA{0, 0, 0, 0},
+		},
+		{
+			Name:     "screenshake-shifted-in",
+			Effect:   bamennutil.ScreenShakeEffect{OffsetX: 1},
+			X:        2,
+			Y:        1,
+			Expected: color.RGBA{255, 255, 255, 255},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			src := ebiten.NewImage(3, 3)
+			src.Fill(color.White)
+			dst := ebiten.NewImage(3, 3)
+
+			c.Effect.Apply(dst, src)
+
+			ar, ag, ab, aa := dst.At(c.X, c.Y).RGBA()
+			er, eg, eb, ea := c.Expected.RGBA()
+			if ar != er || ag != eg || ab != eb || aa != ea {
+				t.Errorf("expected RGBA=%d,%d,%d,%d, but got %d,%d,%d,%d", er, eg, eb, ea, ar, ag, ab, aa)
+			}
+		})
+	}
+}
+
+func TestColorGradingEffectLargeLookupTable(t *testing.T) {
+	// The lookup table of 4 slices is 16x4, which is larger than the screen.
+	lut := ebiten.NewImage(16, 4)
+	lut.Fill(color.RGBA{255, 0, 0, 255})
+	e := bamennutil.NewColorGradingEffect(lut)
+
+	src := ebiten.NewImage(3, 3)
+	src.Fill(color.White)
+	dst := ebiten.NewImage(3, 3)
+
+	e.Apply(dst, src)
+
+	expected := color.RGBA{255, 0, 0, 255}
+	if got := dst.At(1, 1); got != expected {
+		t.Errorf("expected %v, but got %v", expected, got)
+	}
+}
@@ -0,0 +1,88 @@
+package bamennutil
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// lazyShader compiles a Kage shader on the first use.
+type lazyShader struct {
+	source []byte
+	once   sync.Once
+	shader *ebiten.Shader
+}
+
+func newLazyShader(source string) *lazyShader {
+	return &lazyShader{source: []byte(source)}
+}
+
+// get returns the compiled shader. It panics if the compilation fails.
+func (s *lazyShader) get() *ebiten.Shader {
+	s.once.Do(func() {
+		sh, err := ebiten.NewShader(s.source)
+		if err != nil {
+			panic(fmt.Sprintf("bamennutil: compiling the shader failed: %v", err))
+		}
+		s.shader = sh
+	})
+	return s.shader
+}
+
+// drawShader draws src onto dst with shader. dst and src must be the same size.
+func drawShader(dst, src *ebiten.Image, shader *lazyShader, uniforms map[string]any) {
+	size := src.Bounds().Size()
+	origin := dst.Bounds().Min
+	op := &ebiten.DrawRectShaderOptions{}
+	op.GeoM.Translate(float64(origin.X), float64(origin.Y))
+	op.Images[0] = src
+	op.Uniforms = uniforms
+	dst.DrawRectShader(size.X, size.Y, shader.get(), op)
+}
+
+// colorUniform converts clr to a uniform value of vec4 with premultiplied alpha.
+func colorUniform(clr color.Color) []float32 {
+	if clr == nil {
+		return []float32{0, 0, 0, 1}
+	}
+	r, g, b, a := clr.RGBA()
+	return []float32{float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff, float32(a) / 0xffff}
+}
+
+// ShaderDrawer can be used to draw LinearTransitions with a Kage shader. The shader replaces the screen.
+// The screen is passed as imageSrc0, and the following uniforms are passed:
+//
+//   - Rate float: the rate that increases to 1 at FrameToSwitch and decreases to 0 afterwards, eased by the Easing.
+//   - Progress float: the EasedRate of the LinearTransitionProgress.
+//   - Color vec4: the Color with premultiplied alpha.
+type ShaderDrawer struct {
+	Shader *ebiten.Shader // Shader is the Kage shader. Nothing is drawn if it is nil.
+	Color  color.Color    // Color is passed to the shader. The default is black.
+	buffer gameutil.Buffer
+}
+
+func (d *ShaderDrawer) Draw(screen *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	if d.Shader == nil {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	buffer := d.buffer.Get(size.X, size.Y)
+	buffer.DrawImage(screen, nil)
+
+	origin := screen.Bounds().Min
+	op := &ebiten.DrawRectShaderOptions{}
+	op.GeoM.Translate(float64(origin.X), float64(origin.Y))
+	op.Images[0] = buffer
+	op.Uniforms = map[string]any{
+		"Rate":     float32(switchRate(progress)),
+		"Progress": float32(progress.EasedRate()),
+		"Color":    colorUniform(d.Color),
+	}
+	op.Blend = ebiten.BlendCopy
+	screen.DrawRectShader(size.X, size.Y, d.Shader, op)
+}
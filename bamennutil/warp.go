@@ -0,0 +1,287 @@
+package bamennutil
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/noppikinatta/bamenn"
+)
+
+type warpScene struct {
+	name string
+	fn   func() (ebiten.Game, error)
+}
+
+type warpTransition struct {
+	name       string
+	transition bamenn.Transition
+}
+
+// WarpConsole wraps a bamenn.Sequence and provides a debug console to switch to any scene known to the bamenn.Sequence directly.
+// The known scenes are the keys of the bamenn.SceneCache of the bamenn.Sequence and the scenes added by AddScene.
+// It is opened with ToggleKey. In the console, Up/Down selects a scene, Left/Right selects a Transition and Enter switches.
+// While it is open, the bamenn.Sequence is not updated.
+type WarpConsole struct {
+	gameWrapper
+	ToggleKey       ebiten.Key // ToggleKey is the key to open and close the console. The default is F2.
+	seq             *bamenn.Sequence
+	scenes          []warpScene
+	transitions     []warpTransition
+	open            bool
+	sceneIndex      int
+	transitionIndex int
+	err             error // err is the last error of Warp shown in the console.
+}
+
+// NewWarpConsole creates a new WarpConsole instance. bamenn.NopTransition is registered as "none".
+func NewWarpConsole(seq *bamenn.Sequence) *WarpConsole {
+	return &WarpConsole{
+		gameWrapper: gameWrapper{game: seq},
+		ToggleKey:   ebiten.KeyF2,
+		seq:         seq,
+		transitions: []warpTransition{{name: "none", transition: bamenn.NopTransition}},
+	}
+}
+
+// AddScene registers a function to create the scene with the name.
+// It is needed only for scenes that are not registered in the bamenn.SceneCache of the bamenn.Sequence.
+func (c *WarpConsole) AddScene(name string, fn func() ebiten.Game) {
+	c.scenes = append(c.scenes, warpScene{name: name, fn: func() (ebiten.Game, error) { return fn(), nil }})
+}
+
+// AddTransition registers the Transition with the name.
+func (c *WarpConsole) AddTransition(name string, transition bamenn.Transition) {
+	c.transitions = append(c.transitions, warpTransition{name: name, transition: transition})
+}
+
+// SceneNames returns the names of the known scenes.
+// The keys of the bamenn.SceneCache come first in ascending order, followed by the scenes added by AddScene in the order of registration.
+func (c *WarpConsole) SceneNames() []string {
+	scenes := c.knownScenes()
+	names := make([]string, len(scenes))
+	for i, s := range scenes {
+		names[i] = s.name
+	}
+	return names
+}
+
+// knownScenes returns the scenes of the bamenn.SceneCache and the scenes added by AddScene.
+func (c *WarpConsole) knownScenes() []warpScene {
+	cache := c.seq.SceneCache()
+	if cache == nil {
+		return c.scenes
+	}
+
+	keys := cache.Keys()
+	scenes := make([]warpScene, 0, len(keys)+len(c.scenes))
+	for _, key := range keys {
+		scenes = append(scenes, warpScene{name: key, fn: func() (ebiten.Game, error) { return cache.Get(key) }})
+	}
+	for _, s := range c.scenes {
+		if !slices.Contains(keys, s.name) {
+			scenes = append(scenes, s)
+		}
+	}
+	return scenes
+}
+
+// Warp switches the bamenn.Sequence to the scene with the Transition by their names.
+// The error is also shown in the console until the next Warp.
+func (c *WarpConsole) Warp(sceneName, transitionName string) error {
+	c.err = c.warp(sceneName, transitionName)
+	return c.err
+}
+
+func (c *WarpConsole) warp(sceneName, transitionName string) error {
+	s, err := c.scene(sceneName)
+	if err != nil {
+		return err
+	}
+	t, err := c.transition(transitionName)
+	if err != nil {
+		return err
+	}
+
+	// The scene is not created during a transition because SwitchWithTransition would reject it.
+	if c.seq.Transition() != nil {
+		return fmt.Errorf("bamennutil: cannot warp to %q during a transition", sceneName)
+	}
+	g, err := s.fn()
+	if err != nil {
+		return err
+	}
+	if !c.seq.SwitchWithTransition(g, t.transition) {
+		return fmt.Errorf("bamennutil: cannot warp to %q during a transition", sceneName)
+	}
+	return nil
+}
+
+// StartAt sets the scene with the name as the first scene of the bamenn.Sequence.
+// It is intended for use before the game starts.
+func (c *WarpConsole) StartAt(sceneName string) error {
+	s, err := c.scene(sceneName)
+	if err != nil {
+		return err
+	}
+	g, err := s.fn()
+	if err != nil {
+		return err
+	}
+	c.seq.SetFirst(g)
+	return nil
+}
+
+// StartFromEnv calls StartAt with the value of the environment variable if it is set.
+// It returns true if the first scene is changed.
+func (c *WarpConsole) StartFromEnv(key string) (bool, error) {
+	name, ok := os.LookupEnv(key)
+	if !ok || name == "" {
+		return false, nil
+	}
+	if err := c.StartAt(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StartFlag returns a flag.Value that calls StartAt with the value of the command-line flag.
+// For example: flag.Var(console.StartFlag(), "scene", "the name of the first scene")
+func (c *WarpConsole) StartFlag() *WarpStartFlag {
+	return &WarpStartFlag{console: c}
+}
+
+// WarpStartFlag is a flag.Value to start a game at the named scene.
+type WarpStartFlag struct {
+	console *WarpConsole
+	name    string
+}
+
+// String is flag.Value implementation.
+func (f *WarpStartFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.name
+}
+
+// Set is flag.Value implementation.
+func (f *WarpStartFlag) Set(name string) error {
+	if err := f.console.StartAt(name); err != nil {
+		return err
+	}
+	f.name = name
+	return nil
+}
+
+func (c *WarpConsole) scene(name string) (warpScene, error) {
+	for _, s := range c.knownScenes() {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return warpScene{}, fmt.Errorf("bamennutil: unknown scene %q", name)
+}
+
+func (c *WarpConsole) transition(name string) (warpTransition, error) {
+	for _, t := range c.transitions {
+		if t.name == name {
+			return t, nil
+		}
+	}
+	return warpTransition{}, fmt.Errorf("bamennutil: unknown transition %q", name)
+}
+
+// SetOpen opens or closes the console.
+func (c *WarpConsole) SetOpen(open bool) {
+	c.open = open
+}
+
+// Opened returns true if the console is open.
+func (c *WarpConsole) Opened() bool {
+	return c.open
+}
+
+// Update is ebiten.Game implementation.
+func (c *WarpConsole) Update() error {
+	if inpututil.IsKeyJustPressed(c.ToggleKey) {
+		c.open = !c.open
+	}
+	if !c.open {
+		return c.seq.Update()
+	}
+
+	scenes := c.knownScenes()
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		c.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		c.sceneIndex = wrapIndex(c.sceneIndex-1, len(scenes))
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		c.sceneIndex = wrapIndex(c.sceneIndex+1, len(scenes))
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		c.transitionIndex = wrapIndex(c.transitionIndex-1, len(c.transitions))
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		c.transitionIndex = wrapIndex(c.transitionIndex+1, len(c.transitions))
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		if len(scenes) == 0 {
+			return nil
+		}
+		// The error is shown in the console by String.
+		if err := c.Warp(scenes[wrapIndex(c.sceneIndex, len(scenes))].name, c.transitions[c.transitionIndex].name); err == nil {
+			c.open = false
+		}
+	}
+
+	return nil
+}
+
+func wrapIndex(i, n int) int {
+	if n == 0 {
+		return 0
+	}
+	return (i%n + n) % n
+}
+
+// Draw is ebiten.Game implementation.
+func (c *WarpConsole) Draw(screen *ebiten.Image) {
+	c.seq.Draw(screen)
+	if !c.open {
+		return
+	}
+
+	b := screen.Bounds()
+	o := ebiten.DrawImageOptions{}
+	o.ColorScale.ScaleWithColor(color.RGBA{A: 192})
+	o.GeoM.Scale(float64(b.Dx()), float64(b.Dy()))
+	o.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+	screen.DrawImage(dummyWhitePixel, &o)
+
+	ebitenutil.DebugPrintAt(screen, c.String(), b.Min.X, b.Min.Y)
+}
+
+// String returns the text drawn by the console.
+func (c *WarpConsole) String() string {
+	b := strings.Builder{}
+
+	b.WriteString("WARP (Up/Down: scene, Left/Right: transition, Enter: warp)\n")
+	fmt.Fprintf(&b, "transition: < %s >\n", c.transitions[c.transitionIndex].name)
+	scenes := c.knownScenes()
+	for i, s := range scenes {
+		cursor := " "
+		if i == wrapIndex(c.sceneIndex, len(scenes)) {
+			cursor = ">"
+		}
+		fmt.Fprintf(&b, "%s %s\n", cursor, s.name)
+	}
+	if c.err != nil {
+		fmt.Fprintf(&b, "error: %v\n", c.err)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package bamennutil_test
+
+import (
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/bamennutil"
+)
+
+func TestWarpConsoleWarp(t *testing.T) {
+	title := &dummyScene{}
+	stage := &dummyScene{}
+
+	seq := bamenn.NewSequence(title)
+	c := bamennutil.NewWarpConsole(seq)
+	c.AddScene("title", func() ebiten.Game { return title })
+	c.AddScene("stage", func() ebiten.Game { return stage })
+
+	if err := c.Warp("stage", "none"); err != nil {
+		t.Fatalf("unexpected err on Warp(): %v", err)
+	}
+	if err := c.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if seq.Current() != stage {
+		t.Errorf("current scene should be stage")
+	}
+
+	if err := c.Warp("unknown", "none"); err == nil {
+		t.Errorf("Warp() should fail with unknown scene")
+	}
+	if err := c.Warp("title", "unknown"); err == nil {
+		t.Errorf("Warp() should fail with unknown transition")
+	}
+	if !strings.Contains(c.String(), "error: ") {
+		t.Errorf("the last error should be shown:\n%s", c.String())
+	}
+
+	if err := c.Warp("title", "none"); err != nil {
+		t.Fatalf("unexpected err on Warp(): %v", err)
+	}
+	if strings.Contains(c.String(), "error: ") {
+		t.Errorf("the error should be cleared by Warp():\n%s", c.String())
+	}
+}
+
+func TestWarpConsoleSceneCache(t *testing.T) {
+	title := &dummyScene{}
+	stage := &dummyScene{}
+	extra := &dummyScene{}
+
+	cache := bamenn.NewSceneCache(2)
+	cache.Register("title", func() ebiten.Game { return title })
+	cache.Register("stage", func() ebiten.Game { return stage })
+
+	seq := bamenn.NewSequence(title)
+	seq.SetSceneCache(cache)
+	c := bamennutil.NewWarpConsole(seq)
+	c.AddScene("extra", func() ebiten.Game { return extra })
+	c.AddScene("title", func() ebiten.Game { return extra })
+
+	if names := c.SceneNames(); !slices.Equal(names, []string{"stage", "title", "extra"}) {
+		t.Errorf("scene names expected [stage title extra], but got %v", names)
+	}
+
+	if err := c.Warp("stage", "none"); err != nil {
+		t.Fatalf("unexpected err on Warp(): %v", err)
+	}
+	if err := c.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if seq.Current() != stage {
+		t.Errorf("current scene should be stage")
+	}
+	if !cache.Cached("stage") {
+		t.Errorf("stage should be got from the cache")
+	}
+}
+
+func TestWarpConsoleStart(t *testing.T) {
+	cases := []struct {
+		Name     string
+		StartFn  func(c *bamennutil.WarpConsole) error
+		Expected string
+	}{
+		{
+			Name: "env",
+			StartFn: func(c *bamennutil.WarpConsole) error {
+				t.Setenv("BAMENN_TEST_SCENE", "stage")
+				_, err := c.StartFromEnv("BAMENN_TEST_SCENE")
+				return err
+			},
+			Expected: "stage",
+		},
+		{
+			Name: "env-not-set",
+			StartFn: func(c *bamennutil.WarpConsole) error {
+				_, err := c.StartFromEnv("BAMENN_TEST_SCENE_NOT_SET")
+				return err
+			},
+			Expected: "title",
+		},
+		{
+			Name: "flag",
+			StartFn: func(c *bamennutil.WarpConsole) error {
+				fs := flag.NewFlagSet("test", flag.ContinueOnError)
+				fs.Var(c.StartFlag(), "scene", "")
+				return fs.Parse([]string{"-scene", "stage"})
+			},
+			Expected: "stage",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			scenes := map[string]*dummyScene{"title": {}, "stage": {}}
+
+			seq := bamenn.NewSequence(scenes["title"])
+			console := bamennutil.NewWarpConsole(seq)
+			console.AddScene("title", func() ebiten.Game { return scenes["title"] })
+			console.AddScene("stage", func() ebiten.Game { return scenes["stage"] })
+
+			if err := c.StartFn(console); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if seq.Current() != scenes[c.Expected] {
+				t.Errorf("first scene should be %s", c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package bamennutil
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// gameWrapper forwards ebiten.Game to the wrapped ebiten.Game.
+// The other interfaces of bamenn are looked up in the wrapped ebiten.Game through bamenn.Wrapper.
+type gameWrapper struct {
+	game ebiten.Game
+}
+
+// Update is ebiten.Game implementation.
+func (w gameWrapper) Update() error {
+	return w.game.Update()
+}
+
+// Draw is ebiten.Game implementation.
+func (w gameWrapper) Draw(screen *ebiten.Image) {
+	w.game.Draw(screen)
+}
+
+// Layout is ebiten.Game implementation.
+func (w gameWrapper) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return w.game.Layout(outsideWidth, outsideHeight)
+}
+
+// LayoutF is ebiten.LayoutFer implementation.
+func (w gameWrapper) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	return gameutil.LayoutF(w.game, outsideWidth, outsideHeight)
+}
+
+// DrawFinalScreen is ebiten.FinalScreenDrawer implementation.
+func (w gameWrapper) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	drawFinalScreen(w.game, screen, offscreen, geoM)
+}
+
+// Unwrap is bamenn.Wrapper implementation.
+func (w gameWrapper) Unwrap() ebiten.Game {
+	return w.game
+}
+
+// drawFinalScreen calls ebiten.FinalScreenDrawer.DrawFinalScreen if g implements it, otherwise the default implementation.
+func drawFinalScreen(g ebiten.Game, screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	if f, ok := g.(ebiten.FinalScreenDrawer); ok {
+		f.DrawFinalScreen(screen, offscreen, geoM)
+		return
+	}
+	bamenn.DefaultDrawFinalScreen(screen, offscreen, geoM)
+}
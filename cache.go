@@ -0,0 +1,111 @@
+package bamenn
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// OnDisposer is an interface that executes processing when a scene is evicted from SceneCache.
+type OnDisposer interface {
+	// OnDispose is called when the scene is evicted. The scene is not used after that.
+	OnDispose()
+}
+
+// SceneCacheStats represents statistics of SceneCache.
+type SceneCacheStats struct {
+	Hits      int // Hits is the number of times a scene is reused.
+	Misses    int // Misses is the number of times a scene is created.
+	Evictions int // Evictions is the number of times a scene is evicted.
+}
+
+type sceneCacheEntry struct {
+	key  string
+	game ebiten.Game
+}
+
+// SceneCache keeps up to the capacity of recently used scenes alive.
+// The least recently used scenes are evicted when Sequence switches scenes.
+type SceneCache struct {
+	capacity  int
+	factories map[string]func() ebiten.Game
+	entries   *list.List // entries are ordered from the most recently used.
+	index     map[string]*list.Element
+	stats     SceneCacheStats
+}
+
+// NewSceneCache creates a new SceneCache instance. The capacity is at least 1.
+func NewSceneCache(capacity int) *SceneCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SceneCache{
+		capacity:  capacity,
+		factories: make(map[string]func() ebiten.Game),
+		entries:   list.New(),
+		index:     make(map[string]*list.Element),
+	}
+}
+
+// Register registers the function to create the scene of the key.
+func (c *SceneCache) Register(key string, factory func() ebiten.Game) {
+	c.factories[key] = factory
+}
+
+// Keys returns the registered keys in ascending order.
+func (c *SceneCache) Keys() []string {
+	keys := make([]string, 0, len(c.factories))
+	for key := range c.factories {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Get returns the scene of the key. If it is not cached, it is created by the registered function.
+// Get does not evict scenes. They are evicted when Sequence switches scenes.
+func (c *SceneCache) Get(key string) (ebiten.Game, error) {
+	if e, ok := c.index[key]; ok {
+		c.entries.MoveToFront(e)
+		c.stats.Hits++
+		return e.Value.(*sceneCacheEntry).game, nil
+	}
+
+	factory, ok := c.factories[key]
+	if !ok {
+		return nil, fmt.Errorf("bamenn: scene %q is not registered", key)
+	}
+
+	g := factory()
+	c.index[key] = c.entries.PushFront(&sceneCacheEntry{key: key, game: g})
+	c.stats.Misses++
+	return g, nil
+}
+
+// Cached returns true if the scene of the key is alive in it.
+func (c *SceneCache) Cached(key string) bool {
+	_, ok := c.index[key]
+	return ok
+}
+
+// Stats returns the statistics of it.
+func (c *SceneCache) Stats() SceneCacheStats {
+	return c.stats
+}
+
+// trim evicts the least recently used scenes over the capacity except keep.
+func (c *SceneCache) trim(keep ebiten.Game) {
+	for e := c.entries.Back(); e != nil && c.entries.Len() > c.capacity; {
+		prev := e.Prev()
+		entry := e.Value.(*sceneCacheEntry)
+		if entry.game != keep {
+			c.entries.Remove(e)
+			delete(c.index, entry.key)
+			c.stats.Evictions++
+			callIfImpl(entry.game, func(o OnDisposer) { o.OnDispose() })
+		}
+		e = prev
+	}
+}
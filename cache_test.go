@@ -0,0 +1,66 @@
+package bamenn_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestSequenceSceneCache(t *testing.T) {
+	r := recorder{}
+
+	cache := bamenn.NewSceneCache(2)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Register(key, func() ebiten.Game {
+			r.Append(key, "create")
+			return &disposerForTest{gameForTest: gameForTest{Name: key, Recorder: &r, UpdateFn: func() error { return nil }}}
+		})
+	}
+
+	first, err := cache.Get("a")
+	if err != nil {
+		t.Fatalf("unexpected err on Get(): %v", err)
+	}
+	seq := bamenn.NewSequence(first)
+	seq.SetSceneCache(cache)
+
+	for _, key := range []string{"b", "c", "b", "a"} {
+		if _, err := seq.SwitchByKey(key, bamenn.NopTransition); err != nil {
+			t.Fatalf("unexpected err on SwitchByKey(): %v", err)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		"a:create",
+		"b:create",
+		"b:update",
+		"c:create",
+		"a:ondispose",
+		"c:update",
+		"b:update",
+		"a:create",
+		"c:ondispose",
+		"a:update",
+	}, r.Log)
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 4 || stats.Evictions != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := seq.SwitchByKey("unknown", bamenn.NopTransition); err == nil {
+		t.Errorf("SwitchByKey() should fail with unknown key")
+	}
+}
+
+type disposerForTest struct {
+	gameForTest
+}
+
+func (d *disposerForTest) OnDispose() {
+	d.gameForTest.append("ondispose")
+}
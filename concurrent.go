@@ -0,0 +1,87 @@
+package bamenn
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// workerPool updates games on persistent goroutines.
+// The goroutines are started by the first update and kept until stop is called. It is not safe for concurrent use.
+type workerPool struct {
+	size   int
+	jobs   chan updateJob
+	wg     sync.WaitGroup
+	panics []any
+}
+
+// updateJob is an Update called by a worker of workerPool.
+type updateJob struct {
+	game  ebiten.Game
+	err   *error
+	panic *any
+}
+
+// update calls Update of the games on up to workers goroutines and waits for all of them.
+// errs[i] is the error of games[i]. If Updates panic, the panic of the lowest index is re-panicked after all of them finish.
+// If workers differs from the last call, the goroutines are replaced.
+func (w *workerPool) update(games []ebiten.Game, errs []error, workers int) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if w.jobs == nil || w.size != workers {
+		w.stop()
+		w.start(workers)
+	}
+
+	if cap(w.panics) < len(games) {
+		w.panics = make([]any, len(games))
+	}
+	panics := w.panics[:len(games)]
+	clear(panics)
+
+	w.wg.Add(len(games))
+	for i, g := range games {
+		w.jobs <- updateJob{game: g, err: &errs[i], panic: &panics[i]}
+	}
+	w.wg.Wait()
+
+	for _, p := range panics {
+		if p != nil {
+			clear(panics)
+			panic(p)
+		}
+	}
+}
+
+// start starts the goroutines.
+func (w *workerPool) start(workers int) {
+	w.size = workers
+	w.jobs = make(chan updateJob, workers)
+	for range workers {
+		go func(jobs <-chan updateJob) {
+			for j := range jobs {
+				*j.panic, *j.err = updateRecovered(j.game)
+				w.wg.Done()
+			}
+		}(w.jobs)
+	}
+}
+
+// stop stops the goroutines if they are started. They are started again by the next update.
+func (w *workerPool) stop() {
+	if w.jobs == nil {
+		return
+	}
+	close(w.jobs)
+	w.jobs = nil
+}
+
+// updateRecovered calls Update of the game and recovers a panic in it.
+func updateRecovered(game ebiten.Game) (recovered any, err error) {
+	defer func() {
+		recovered = recover()
+	}()
+	return nil, game.Update()
+}
@@ -0,0 +1,49 @@
+package bamenn
+
+import "math"
+
+// Easing is a function that maps the progress rate in the range of 0.0~1.0 to an eased rate.
+// It is used by LinearTransition and Scheduler.Tween.
+type Easing func(t float64) float64
+
+// Apply returns the eased rate of t. t is clamped to the range of 0.0~1.0. A nil Easing is linear.
+func (e Easing) Apply(t float64) float64 {
+	t = min(max(t, 0), 1)
+	if e == nil {
+		return t
+	}
+	return e(t)
+}
+
+var (
+	// EaseLinear does not ease.
+	EaseLinear Easing = func(t float64) float64 { return t }
+	// EaseInQuad accelerates from zero velocity.
+	EaseInQuad Easing = func(t float64) float64 { return t * t }
+	// EaseOutQuad decelerates to zero velocity.
+	EaseOutQuad Easing = func(t float64) float64 { return 1 - (1-t)*(1-t) }
+	// EaseInOutQuad accelerates until halfway, then decelerates.
+	EaseInOutQuad Easing = func(t float64) float64 {
+		if t < 0.5 {
+			return 2 * t * t
+		}
+		return 1 - 2*(1-t)*(1-t)
+	}
+	// EaseInCubic accelerates from zero velocity.
+	EaseInCubic Easing = func(t float64) float64 { return t * t * t }
+	// EaseOutCubic decelerates to zero velocity.
+	EaseOutCubic Easing = func(t float64) float64 { return 1 - math.Pow(1-t, 3) }
+	// EaseInOutCubic accelerates until halfway, then decelerates.
+	EaseInOutCubic Easing = func(t float64) float64 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		return 1 - 4*math.Pow(1-t, 3)
+	}
+	// EaseInSine accelerates with a sine curve.
+	EaseInSine Easing = func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+	// EaseOutSine decelerates with a sine curve.
+	EaseOutSine Easing = func(t float64) float64 { return math.Sin(t * math.Pi / 2) }
+	// EaseInOutSine accelerates and decelerates with a sine curve.
+	EaseInOutSine Easing = func(t float64) float64 { return (1 - math.Cos(t*math.Pi)) / 2 }
+)
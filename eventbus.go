@@ -0,0 +1,204 @@
+package bamenn
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// EventBusSetter is an interface for a scene that receives an EventBus scoped to its lifetime.
+type EventBusSetter interface {
+	// SetEventBus is called just before OnStarter.OnStart.
+	// Subscriptions made with bus are unsubscribed at OnEnder.OnEnd.
+	SetEventBus(bus *EventBus)
+}
+
+// Subscription represents a function subscribed to an EventBus.
+type Subscription struct {
+	hub    *eventHub
+	typ    reflect.Type
+	fn     func(event any)
+	active bool
+}
+
+// Unsubscribe stops the delivery of events to the function.
+func (s *Subscription) Unsubscribe() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.unsubscribe()
+}
+
+func (s *Subscription) unsubscribe() {
+	if !s.active {
+		return
+	}
+	s.active = false
+	s.hub.handlers[s.typ] = slices.DeleteFunc(s.hub.handlers[s.typ], func(h *Subscription) bool { return h == s })
+}
+
+// Active returns true if it is not unsubscribed.
+func (s *Subscription) Active() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.active
+}
+
+type queuedEvent struct {
+	typ   reflect.Type
+	value any
+}
+
+// eventHub holds the subscriptions and the queued events shared by an EventBus and its scopes.
+// Games of Parallel updated concurrently may publish and subscribe at the same time, so it is guarded by mu.
+type eventHub struct {
+	mu       sync.Mutex // mu guards the hub and all EventBuses and Subscriptions sharing it.
+	handlers map[reflect.Type][]*Subscription
+	queue    []queuedEvent
+	delivery []queuedEvent
+}
+
+// EventBus delivers typed events published by scenes to subscribed scenes.
+// Published events are queued and delivered by Deliver, so the delivery happens at a deterministic point.
+// Sequence and Parallel deliver events in their Update before updating scenes, unless they received the EventBus from their parent.
+type EventBus struct {
+	hub    *eventHub
+	parent *EventBus
+	scopes []*EventBus
+	subs   []*Subscription
+	closed bool
+}
+
+// NewEventBus creates a new EventBus instance.
+func NewEventBus() *EventBus {
+	return &EventBus{hub: &eventHub{handlers: make(map[reflect.Type][]*Subscription)}}
+}
+
+// Scope returns a new EventBus sharing events with it.
+// Subscriptions made with the returned EventBus are unsubscribed when it or its ancestors are closed.
+func (b *EventBus) Scope() *EventBus {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+
+	s := &EventBus{hub: b.hub, parent: b, closed: b.closed}
+	if !b.closed {
+		b.scopes = append(b.scopes, s)
+	}
+	return s
+}
+
+// Close unsubscribes all subscriptions made with it and its scopes.
+func (b *EventBus) Close() {
+	if b == nil {
+		return
+	}
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	b.close()
+}
+
+func (b *EventBus) close() {
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for _, s := range b.subs {
+		s.unsubscribe()
+	}
+	b.subs = nil
+
+	for _, s := range b.scopes {
+		s.parent = nil
+		s.close()
+	}
+	b.scopes = nil
+
+	if b.parent != nil {
+		b.parent.scopes = slices.DeleteFunc(b.parent.scopes, func(s *EventBus) bool { return s == b })
+		b.parent = nil
+	}
+}
+
+// Deliver calls the subscribed functions with the events published before the call, in the order of publication and subscription.
+// Events published during Deliver are delivered by the next Deliver.
+// The subscribed functions are called without the lock, so they can publish, subscribe and unsubscribe.
+func (b *EventBus) Deliver() {
+	h := b.hub
+	h.mu.Lock()
+	h.delivery, h.queue = h.queue, h.delivery[:0]
+	delivery := h.delivery
+	h.mu.Unlock()
+
+	for _, e := range delivery {
+		h.mu.Lock()
+		subs := slices.Clone(h.handlers[e.typ])
+		h.mu.Unlock()
+
+		for _, s := range subs {
+			if s.Active() {
+				s.fn(e.value)
+			}
+		}
+	}
+
+	clear(delivery)
+	h.mu.Lock()
+	h.delivery = delivery[:0]
+	h.mu.Unlock()
+}
+
+// Publish queues the event. It is delivered to the functions subscribed to the type T by the next EventBus.Deliver.
+func Publish[T any](bus *EventBus, event T) {
+	h := bus.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queue = append(h.queue, queuedEvent{typ: reflect.TypeFor[T](), value: event})
+}
+
+// Subscribe subscribes fn to events of the type T.
+// If the EventBus is already closed, the returned Subscription is inactive.
+func Subscribe[T any](bus *EventBus, fn func(event T)) *Subscription {
+	s := &Subscription{
+		hub: bus.hub,
+		typ: reflect.TypeFor[T](),
+		fn:  func(event any) { fn(event.(T)) },
+	}
+	bus.hub.mu.Lock()
+	defer bus.hub.mu.Unlock()
+	if bus.closed {
+		return s
+	}
+
+	s.active = true
+	bus.hub.handlers[s.typ] = append(bus.hub.handlers[s.typ], s)
+	bus.subs = append(bus.subs, s)
+	return s
+}
+
+// eventBusOwner holds the EventBus of a Sequence or a Parallel.
+type eventBusOwner struct {
+	bus  *EventBus
+	owns bool // owns is true if the EventBus is not received from the parent.
+}
+
+// get returns the EventBus. A new EventBus is created if it has no EventBus.
+func (o *eventBusOwner) get() *EventBus {
+	if o.bus == nil {
+		o.bus = NewEventBus()
+		o.owns = true
+	}
+	return o.bus
+}
+
+// set sets the EventBus received from the parent.
+func (o *eventBusOwner) set(bus *EventBus) {
+	o.bus = bus
+	o.owns = false
+}
+
+// deliver delivers events if it owns the EventBus.
+func (o *eventBusOwner) deliver() {
+	if o.owns {
+		o.bus.Deliver()
+	}
+}
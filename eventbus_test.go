@@ -0,0 +1,130 @@
+package bamenn_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/noppikinatta/bamenn"
+)
+
+type scoreForTest struct {
+	Score int
+}
+
+func TestEventBus(t *testing.T) {
+	r := recorder{}
+	bus := bamenn.NewEventBus()
+
+	scope := bus.Scope()
+	bamenn.Subscribe(bus, func(e scoreForTest) { r.Append("root", fmt.Sprint(e.Score)) })
+	bamenn.Subscribe(scope, func(e scoreForTest) {
+		r.Append("scope", fmt.Sprint(e.Score))
+		bamenn.Publish(bus, scoreForTest{Score: e.Score * 10})
+	})
+	bamenn.Subscribe(bus, func(e string) { r.Append("root", e) })
+
+	bamenn.Publish(bus, scoreForTest{Score: 1})
+	bamenn.Publish(scope, "hello")
+	r.Append("deliver", "1")
+	bus.Deliver()
+
+	scope.Close()
+	r.Append("deliver", "2")
+	bus.Deliver()
+
+	compareLogs(t, []string{
+		"deliver:1",
+		"root:1",
+		"scope:1",
+		"root:hello",
+		"deliver:2",
+		"root:10",
+	}, r.Log)
+
+	if s := bamenn.Subscribe(scope, func(e string) {}); s.Active() {
+		t.Errorf("subscriptions of a closed EventBus should be inactive")
+	}
+}
+
+func TestParallelEventBus(t *testing.T) {
+	r := recorder{}
+
+	world := &eventBusSetterForTest{gameForTest: gameForTest{Name: "world", Recorder: &r}}
+	hud := &eventBusSetterForTest{gameForTest: gameForTest{Name: "hud", Recorder: &r}}
+
+	frame := 0
+	world.UpdateFn = func() error {
+		bamenn.Publish(world.bus, scoreForTest{Score: frame})
+		return nil
+	}
+	hud.UpdateFn = func() error { return nil }
+	hud.onSetFn = func() {
+		bamenn.Subscribe(hud.bus, func(e scoreForTest) { r.Append("hud", fmt.Sprintf("score%d", e.Score)) })
+	}
+
+	p := bamenn.NewParallel(world, hud)
+	for range 2 {
+		frame++
+		if err := p.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		"world:seteventbus",
+		"hud:seteventbus",
+		"world:update",
+		"hud:update",
+		"hud:score1",
+		"world:update",
+		"hud:update",
+	}, r.Log)
+}
+
+func TestSequenceEventBus(t *testing.T) {
+	r := recorder{}
+
+	s1 := &eventBusSetterForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}
+	s2 := &eventBusSetterForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r}}
+	s1.onSetFn = func() {
+		bamenn.Subscribe(s1.bus, func(e string) { r.Append("s1", e) })
+	}
+	s2.UpdateFn = func() error {
+		bamenn.Publish(s2.bus, "from s2")
+		return nil
+	}
+
+	seq := bamenn.NewSequence(s1)
+	bamenn.Publish(seq.EventBus(), "from outside")
+	for i := range 3 {
+		if i == 1 {
+			seq.Switch(s2)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		"s1:seteventbus",
+		"s1:from outside",
+		"s1:update",
+		"s2:seteventbus",
+		"s2:update",
+		"s2:update",
+	}, r.Log)
+}
+
+type eventBusSetterForTest struct {
+	gameForTest
+	bus     *bamenn.EventBus
+	onSetFn func()
+}
+
+func (e *eventBusSetterForTest) SetEventBus(bus *bamenn.EventBus) {
+	e.append("seteventbus")
+	e.bus = bus
+	if e.onSetFn != nil {
+		e.onSetFn()
+	}
+}
@@ -0,0 +1,117 @@
+package bamenn
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// InterpolatedDrawer is an interface for an ebiten.Game that draws with the interpolation alpha of FixedStep.
+type InterpolatedDrawer interface {
+	// DrawInterpolated is called instead of ebiten.Game.Draw.
+	// alpha is the progress to the next step in the range of 0.0~1.0.
+	DrawInterpolated(screen *ebiten.Image, alpha float64)
+}
+
+const (
+	// maxStepsPerUpdate limits the number of steps in an Update to avoid the spiral of death.
+	maxStepsPerUpdate = 8
+	// stepTolerance absorbs floating point errors of the accumulated time, e.g. 3 * (1/60) < 2 * (1/40).
+	stepTolerance = 1e-9
+)
+
+// FixedStep wraps an ebiten.Game and calls its Update at a fixed rate regardless of ebiten.TPS.
+// The elapsed time is accumulated by 1/ebiten.TPS per Update, or by the actual time if ebiten.SyncWithFPS is used.
+// The other interfaces of the wrapped ebiten.Game are handled through Wrapper. Note that its Scheduler is updated at the rate of ebiten.TPS, not at the fixed rate.
+type FixedStep struct {
+	game        ebiten.Game
+	step        float64
+	accumulated float64
+	lastTime    time.Time
+}
+
+// NewFixedStep creates a new FixedStep instance that calls Update of the game stepsPerSecond times per second.
+func NewFixedStep(game ebiten.Game, stepsPerSecond int) *FixedStep {
+	if stepsPerSecond < 1 {
+		stepsPerSecond = 1
+	}
+	return &FixedStep{game: game, step: 1 / float64(stepsPerSecond)}
+}
+
+// Alpha returns the progress to the next step in the range of 0.0~1.0. It is useful to interpolate drawing.
+func (f *FixedStep) Alpha() float64 {
+	return f.accumulated / f.step
+}
+
+// Update is ebiten.Game implementation.
+// It calls Update of the wrapped ebiten.Game zero or more times according to the elapsed time.
+func (f *FixedStep) Update() error {
+	f.accumulated += f.delta()
+	f.accumulated = min(f.accumulated, f.step*maxStepsPerUpdate)
+
+	for f.accumulated >= f.step*(1-stepTolerance) {
+		f.accumulated = max(f.accumulated-f.step, 0)
+		if err := f.game.Update(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delta returns the elapsed seconds since the last Update.
+func (f *FixedStep) delta() float64 {
+	if tps := ebiten.TPS(); tps > 0 {
+		return 1 / float64(tps)
+	}
+
+	now := time.Now()
+	defer func() { f.lastTime = now }()
+	if f.lastTime.IsZero() {
+		return 0
+	}
+	return now.Sub(f.lastTime).Seconds()
+}
+
+// Draw is ebiten.Game implementation.
+// It calls InterpolatedDrawer.DrawInterpolated if the wrapped ebiten.Game implements it.
+func (f *FixedStep) Draw(screen *ebiten.Image) {
+	if d, ok := f.game.(InterpolatedDrawer); ok {
+		d.DrawInterpolated(screen, f.Alpha())
+		return
+	}
+	f.game.Draw(screen)
+}
+
+// Layout is ebiten.Game implementation.
+func (f *FixedStep) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return f.game.Layout(outsideWidth, outsideHeight)
+}
+
+// LayoutF is ebiten.LayoutFer implementation.
+func (f *FixedStep) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	return gameutil.LayoutF(f.game, outsideWidth, outsideHeight)
+}
+
+// DrawFinalScreen is ebiten.FinalScreenDrawer implementation.
+func (f *FixedStep) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.Image, geoM ebiten.GeoM) {
+	if d, ok := f.game.(ebiten.FinalScreenDrawer); ok {
+		d.DrawFinalScreen(screen, offScreen, geoM)
+	} else {
+		defaultDrawFinalScreenTemporaryImplRemoveItWhenEbitengineV290Released(screen, offScreen, geoM)
+	}
+}
+
+// Unwrap is Wrapper implementation.
+func (f *FixedStep) Unwrap() ebiten.Game {
+	return f.game
+}
+
+// OnStart is OnStarter implementation.
+// The accumulated time is reset.
+func (f *FixedStep) OnStart() {
+	f.accumulated = 0
+	f.lastTime = time.Time{}
+	callIfImpl(f.game, func(o OnStarter) { o.OnStart() })
+}
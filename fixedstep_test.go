@@ -0,0 +1,87 @@
+package bamenn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestFixedStep(t *testing.T) {
+	// ebiten.TPS is 60 by default.
+	cases := []struct {
+		Name           string
+		StepsPerSecond int
+		Updates        int
+		ExpectedSteps  int
+	}{
+		{Name: "same-rate", StepsPerSecond: 60, Updates: 6, ExpectedSteps: 6},
+		{Name: "half-rate", StepsPerSecond: 30, Updates: 6, ExpectedSteps: 3},
+		{Name: "double-rate", StepsPerSecond: 120, Updates: 6, ExpectedSteps: 12},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			steps := 0
+			g := &gameForTest{UpdateFn: func() error { steps++; return nil }}
+			f := bamenn.NewFixedStep(g, c.StepsPerSecond)
+
+			for range c.Updates {
+				if err := f.Update(); err != nil {
+					t.Fatalf("unexpected err on Update(): %v", err)
+				}
+			}
+
+			if steps != c.ExpectedSteps {
+				t.Errorf("expected %d steps, but got %d", c.ExpectedSteps, steps)
+			}
+		})
+	}
+}
+
+func TestFixedStepAlpha(t *testing.T) {
+	g := &interpolatedDrawerForTest{gameForTest: gameForTest{UpdateFn: func() error { return nil }}}
+	f := bamenn.NewFixedStep(g, 40)
+	screen := ebiten.NewImage(1, 1)
+
+	// A step is 1.5 Updates with 60 TPS.
+	expectedAlphas := []float64{2.0 / 3.0, 1.0 / 3.0, 0}
+	for i, expected := range expectedAlphas {
+		if err := f.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		f.Draw(screen)
+
+		if math.Abs(g.alpha-expected) > 1e-9 {
+			t.Errorf("%d: expected alpha %f, but got %f", i, expected, g.alpha)
+		}
+	}
+}
+
+func TestFixedStepEvents(t *testing.T) {
+	r := recorder{}
+	g := &eventsForTest{gameForTest: gameForTest{Name: "s", Recorder: &r}}
+	f := bamenn.NewFixedStep(g, 30)
+
+	g.UpdateFn = func() error { return nil }
+	seq := bamenn.NewSequence(f)
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	seq.Switch(&gameForTest{UpdateFn: g.UpdateFn})
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+
+	compareLogs(t, []string{"s:onstart", "s:onarrival", "s:ondeparture", "s:onend"}, r.Log)
+}
+
+type interpolatedDrawerForTest struct {
+	gameForTest
+	alpha float64
+}
+
+func (d *interpolatedDrawerForTest) DrawInterpolated(screen *ebiten.Image, alpha float64) {
+	d.alpha = alpha
+}
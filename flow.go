@@ -0,0 +1,112 @@
+package bamenn
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Flow runs a child Sequence of scenes as a scene and resolves to a result of type T.
+// Use RunFlow to run it in a parent Sequence.
+type Flow[T any] struct {
+	*Sequence
+	first      ebiten.Game
+	restarting bool // restarting is true until the flow is started from the first scene by RunFlow.
+	parent     *Sequence
+	returnTo   ebiten.Game
+	transition Transition
+	onResult   func(result T)
+	result     T
+	resolved   bool
+	returned   bool
+}
+
+// NewFlow creates a new Flow instance with the first scene of the child flow.
+// Scenes in the flow can switch between themselves with the embedded Sequence.
+func NewFlow[T any](first ebiten.Game) *Flow[T] {
+	return &Flow[T]{Sequence: NewSequence(first), first: first}
+}
+
+// RunFlow switches parent to flow with transition. The flow starts over from its first scene on each run, even if flow is already running in parent.
+// When the flow is resolved, parent starts switching back to the scene running before the flow with transition, and onResult is called with the result at that time, before the transition completes.
+// It returns false if parent is processing another Transition.
+func RunFlow[T any](parent *Sequence, flow *Flow[T], transition Transition, onResult func(result T)) bool {
+	returnTo := parent.current
+	if returnTo == ebiten.Game(flow) {
+		// The flow is run again, so parent switches back to the scene of the previous run.
+		returnTo = flow.returnTo
+	}
+	if !parent.SwitchWithTransition(flow, transition) {
+		return false
+	}
+
+	var zero T
+	flow.restarting = true
+	flow.parent = parent
+	flow.returnTo = returnTo
+	flow.transition = transition
+	flow.onResult = onResult
+	flow.result = zero
+	flow.resolved = false
+	flow.returned = false
+	return true
+}
+
+// Resolve ends the flow with the result. It is usually called by a scene in the flow.
+// Only the first call in a run is effective.
+func (f *Flow[T]) Resolve(result T) {
+	if f.resolved {
+		return
+	}
+	f.result = result
+	f.resolved = true
+}
+
+// Complete is Sequence.Complete shadowed by Flow. It resolves the flow with the zero value of T.
+func (f *Flow[T]) Complete() {
+	var zero T
+	f.Resolve(zero)
+}
+
+// Result returns the result and true if the flow is resolved.
+func (f *Flow[T]) Result() (result T, ok bool) {
+	return f.result, f.resolved
+}
+
+// Completed is Completer implementation.
+// It returns true after the flow is resolved.
+func (f *Flow[T]) Completed() bool {
+	return f.resolved
+}
+
+// Update is ebiten.Game implementation.
+// After the flow is resolved, it tries to switch the parent Sequence back every frame until it succeeds.
+func (f *Flow[T]) Update() error {
+	if err := f.Sequence.Update(); err != nil {
+		return err
+	}
+
+	f.returnIfResolved()
+	return nil
+}
+
+func (f *Flow[T]) returnIfResolved() {
+	if !f.resolved || f.returned || f.parent == nil {
+		return
+	}
+	if !f.parent.SwitchWithTransition(f.returnTo, f.transition) {
+		return
+	}
+
+	f.returned = true
+	if f.onResult != nil {
+		f.onResult(f.result)
+	}
+}
+
+// OnStart is OnStarter implementation. It starts the first scene if the flow is run by RunFlow.
+func (f *Flow[T]) OnStart() {
+	if f.restarting {
+		f.restarting = false
+		f.SetFirst(f.first)
+	}
+	f.Sequence.OnStart()
+}
@@ -0,0 +1,210 @@
+package bamenn_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestFlow(t *testing.T) {
+	r := recorder{}
+
+	s1 := eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r}}
+	f1 := eventsForTest{gameForTest: gameForTest{Name: "f1", Recorder: &r}}
+	f2 := eventsForTest{gameForTest: gameForTest{Name: "f2", Recorder: &r}}
+
+	seq := bamenn.NewSequence(&s1)
+	flow := bamenn.NewFlow[[]string](&f1)
+
+	var results [][]string
+	s1count := 0
+	s1.UpdateFn = func() error {
+		s1count++
+		if s1count > 1 {
+			return ebiten.Termination
+		}
+		bamenn.RunFlow(seq, flow, bamenn.NopTransition, func(items []string) {
+			results = append(results, items)
+		})
+		return nil
+	}
+
+	f1.UpdateFn = func() error {
+		flow.Switch(&f2)
+		return nil
+	}
+	f2.UpdateFn = func() error {
+		flow.Resolve([]string{"potion", "sword"})
+		return nil
+	}
+
+	runForTest(t, seq)
+
+	compareLogs(t, []string{
+		"s1:layout",
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:update",
+		"s1:ondeparture",
+		"s1:draw",
+		"s1:layout",
+		"s1:onend",
+		"f1:onstart",
+		"f1:onarrival",
+		"f1:update",
+		"f1:ondeparture",
+		"f1:draw",
+		"f1:layout",
+		"f1:onend",
+		"f2:onstart",
+		"f2:onarrival",
+		"f2:update",
+		"f2:ondeparture",
+		"f2:draw",
+		"f2:layout",
+		"f2:onend",
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:update",
+	}, r.Log)
+
+	if len(results) != 1 {
+		t.Fatalf("result should be received once, but got %d", len(results))
+	}
+	if len(results[0]) != 2 || results[0][0] != "potion" || results[0][1] != "sword" {
+		t.Errorf("unexpected result: %v", results[0])
+	}
+	if res, ok := flow.Result(); !ok || len(res) != 2 {
+		t.Errorf("flow should be resolved, but got %v, %t", res, ok)
+	}
+}
+
+func TestFlowRestart(t *testing.T) {
+	var updated []string
+
+	s1 := gameForTest{Name: "s1"}
+	f1 := gameForTest{Name: "f1"}
+	f2 := gameForTest{Name: "f2"}
+
+	seq := bamenn.NewSequence(&s1)
+	flow := bamenn.NewFlow[int](&f1)
+
+	var results []int
+	runs := 0
+	s1.UpdateFn = func() error {
+		updated = append(updated, "s1")
+		runs++
+		if runs > 2 {
+			return ebiten.Termination
+		}
+		bamenn.RunFlow(seq, flow, bamenn.NopTransition, func(result int) {
+			results = append(results, result)
+		})
+		return nil
+	}
+	f1.UpdateFn = func() error {
+		updated = append(updated, "f1")
+		flow.Switch(&f2)
+		return nil
+	}
+	f2.UpdateFn = func() error {
+		updated = append(updated, "f2")
+		flow.Resolve(runs)
+		return nil
+	}
+
+	runForTest(t, seq)
+
+	compareLogs(t, []string{"s1", "f1", "f2", "s1", "f1", "f2", "s1"}, updated)
+	if len(results) != 2 || results[0] != 1 || results[1] != 2 {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
+
+func TestFlowComplete(t *testing.T) {
+	s1 := gameForTest{Name: "s1"}
+	f1 := gameForTest{Name: "f1"}
+
+	seq := bamenn.NewSequence(&s1)
+	flow := bamenn.NewFlow[string](&f1)
+
+	var results []string
+	s1count := 0
+	s1.UpdateFn = func() error {
+		s1count++
+		if s1count > 1 {
+			return ebiten.Termination
+		}
+		bamenn.RunFlow(seq, flow, bamenn.NopTransition, func(result string) {
+			results = append(results, result)
+		})
+		return nil
+	}
+	f1.UpdateFn = func() error {
+		flow.Complete()
+		return nil
+	}
+
+	runForTest(t, seq)
+
+	if !flow.Completed() {
+		t.Error("flow should be completed")
+	}
+	if len(results) != 1 || results[0] != "" {
+		t.Errorf("flow should be resolved with the zero value, but got %v", results)
+	}
+}
+
+func TestFlowRunAgain(t *testing.T) {
+	var updated []string
+
+	s1 := gameForTest{Name: "s1"}
+	f1 := gameForTest{Name: "f1"}
+	f2 := gameForTest{Name: "f2"}
+
+	seq := bamenn.NewSequence(&s1)
+	flow := bamenn.NewFlow[int](&f1)
+
+	var results []int
+	runFlow := func() {
+		bamenn.RunFlow(seq, flow, bamenn.NopTransition, func(result int) {
+			results = append(results, result)
+		})
+	}
+
+	s1count := 0
+	s1.UpdateFn = func() error {
+		updated = append(updated, "s1")
+		s1count++
+		if s1count > 1 {
+			return ebiten.Termination
+		}
+		runFlow()
+		return nil
+	}
+	f1.UpdateFn = func() error {
+		updated = append(updated, "f1")
+		flow.Switch(&f2)
+		return nil
+	}
+	f2count := 0
+	f2.UpdateFn = func() error {
+		updated = append(updated, "f2")
+		f2count++
+		if f2count == 1 {
+			// The flow is run again while it is running.
+			runFlow()
+			return nil
+		}
+		flow.Resolve(f2count)
+		return nil
+	}
+
+	runForTest(t, seq)
+
+	compareLogs(t, []string{"s1", "f1", "f2", "f1", "f2", "s1"}, updated)
+	if len(results) != 1 || results[0] != 2 {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
@@ -87,6 +87,7 @@ func (r *recorder) Append(name, logType string) {
 type gameForTest struct {
 	Name             string
 	UpdateFn         func() error
+	DrawFn           func(screen *ebiten.Image)
 	OnArrivalFn      func()
 	Recorder         *recorder
 	LayoutW, LayoutH int
@@ -109,6 +110,9 @@ func (g *gameForTest) Update() error {
 
 func (g *gameForTest) Draw(screen *ebiten.Image) {
 	g.append("draw")
+	if g.DrawFn != nil {
+		g.DrawFn(screen)
+	}
 }
 
 func (g *gameForTest) Layout(outsideWidth int, outsideHeight int) (screenWidth int, screenHeight int) {
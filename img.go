@@ -0,0 +1,19 @@
+package bamenn
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	whiteImageBase = ebiten.NewImage(3, 3)
+
+	// whitePixel is a 1x1 white pixel image.
+	whitePixel = whiteImageBase.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImageBase.Fill(color.White)
+}
@@ -0,0 +1,128 @@
+package bamenn
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// InputHandler is an interface for a layer of Parallel that handles input routed from the top layer.
+type InputHandler interface {
+	// HandleInput is called before ebiten.Game.Update if the input routing of Parallel is enabled.
+	// The layer should mark input it handled as consumed so that the layers beneath it ignore the input.
+	HandleInput(input *Input)
+}
+
+// Modal is an interface for a layer of Parallel that blocks input to the layers beneath it.
+type Modal interface {
+	// Modal returns true if the layers beneath it are not offered input.
+	Modal() bool
+}
+
+// Input represents the input of a frame routed to the layers of Parallel, and tracks which of it is consumed.
+type Input struct {
+	keys         map[ebiten.Key]struct{}
+	mouseButtons map[ebiten.MouseButton]struct{}
+	touches      map[ebiten.TouchID]struct{}
+	all          bool
+}
+
+// reset clears the consumption for a new frame.
+func (i *Input) reset() {
+	clear(i.keys)
+	clear(i.mouseButtons)
+	clear(i.touches)
+	i.all = false
+}
+
+func (i *Input) init() {
+	if i.keys != nil {
+		return
+	}
+	i.keys = make(map[ebiten.Key]struct{})
+	i.mouseButtons = make(map[ebiten.MouseButton]struct{})
+	i.touches = make(map[ebiten.TouchID]struct{})
+}
+
+// ConsumeKey marks the key as consumed.
+func (i *Input) ConsumeKey(key ebiten.Key) {
+	i.init()
+	i.keys[key] = struct{}{}
+}
+
+// ConsumeMouseButton marks the mouse button as consumed.
+func (i *Input) ConsumeMouseButton(button ebiten.MouseButton) {
+	i.init()
+	i.mouseButtons[button] = struct{}{}
+}
+
+// ConsumeTouch marks the touch as consumed.
+func (i *Input) ConsumeTouch(id ebiten.TouchID) {
+	i.init()
+	i.touches[id] = struct{}{}
+}
+
+// ConsumeAll marks all input as consumed.
+func (i *Input) ConsumeAll() {
+	i.all = true
+}
+
+// KeyConsumed returns true if the key is consumed.
+func (i *Input) KeyConsumed(key ebiten.Key) bool {
+	_, ok := i.keys[key]
+	return ok || i.all
+}
+
+// MouseButtonConsumed returns true if the mouse button is consumed.
+func (i *Input) MouseButtonConsumed(button ebiten.MouseButton) bool {
+	_, ok := i.mouseButtons[button]
+	return ok || i.all
+}
+
+// TouchConsumed returns true if the touch is consumed.
+func (i *Input) TouchConsumed(id ebiten.TouchID) bool {
+	_, ok := i.touches[id]
+	return ok || i.all
+}
+
+// AllConsumed returns true if ConsumeAll is called.
+func (i *Input) AllConsumed() bool {
+	return i.all
+}
+
+// IsKeyPressed returns true if the key is pressed and not consumed.
+func (i *Input) IsKeyPressed(key ebiten.Key) bool {
+	return !i.KeyConsumed(key) && ebiten.IsKeyPressed(key)
+}
+
+// IsKeyJustPressed returns true if the key is just pressed and not consumed.
+func (i *Input) IsKeyJustPressed(key ebiten.Key) bool {
+	return !i.KeyConsumed(key) && inpututil.IsKeyJustPressed(key)
+}
+
+// IsMouseButtonPressed returns true if the mouse button is pressed and not consumed.
+func (i *Input) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return !i.MouseButtonConsumed(button) && ebiten.IsMouseButtonPressed(button)
+}
+
+// IsMouseButtonJustPressed returns true if the mouse button is just pressed and not consumed.
+func (i *Input) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return !i.MouseButtonConsumed(button) && inpututil.IsMouseButtonJustPressed(button)
+}
+
+// IsMouseButtonJustReleased returns true if the mouse button is just released and not consumed.
+func (i *Input) IsMouseButtonJustReleased(button ebiten.MouseButton) bool {
+	return !i.MouseButtonConsumed(button) && inpututil.IsMouseButtonJustReleased(button)
+}
+
+// AppendJustPressedTouchIDs appends the IDs of touches that are just pressed and not consumed to ids.
+func (i *Input) AppendJustPressedTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	if i.all {
+		return ids
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		if !i.TouchConsumed(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
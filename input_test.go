@@ -0,0 +1,90 @@
+package bamenn_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestParallelInputRouting(t *testing.T) {
+	r := recorder{}
+
+	world := &inputHandlerForTest{gameForTest: gameForTest{Name: "world", Recorder: &r, UpdateFn: func() error { return nil }}}
+	pause := &inputHandlerForTest{gameForTest: gameForTest{Name: "pause", Recorder: &r, UpdateFn: func() error { return nil }}}
+	hud := &inputHandlerForTest{gameForTest: gameForTest{Name: "hud", Recorder: &r, UpdateFn: func() error { return nil }}}
+	hud.handleFn = func(input *bamenn.Input) { input.ConsumeKey(ebiten.KeyTab) }
+
+	p := bamenn.NewParallel(world, pause, hud)
+
+	if err := p.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+
+	p.SetInputRouting(true)
+	for _, modal := range []bool{false, true} {
+		pause.modal = modal
+		if err := p.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		// input routing is disabled
+		"world:update",
+		"pause:update",
+		"hud:update",
+		// pause is not modal
+		"hud:handleinput:tab=false",
+		"pause:handleinput:tab=true",
+		"world:handleinput:tab=true",
+		"world:update",
+		"pause:update",
+		"hud:update",
+		// pause is modal
+		"hud:handleinput:tab=false",
+		"pause:handleinput:tab=true",
+		"world:update",
+		"pause:update",
+		"hud:update",
+	}, r.Log)
+}
+
+func TestInput(t *testing.T) {
+	input := bamenn.Input{}
+	if input.KeyConsumed(ebiten.KeyA) {
+		t.Errorf("no key should be consumed")
+	}
+
+	input.ConsumeKey(ebiten.KeyA)
+	input.ConsumeMouseButton(ebiten.MouseButtonLeft)
+	if !input.KeyConsumed(ebiten.KeyA) || input.KeyConsumed(ebiten.KeyB) {
+		t.Errorf("only KeyA should be consumed")
+	}
+	if !input.MouseButtonConsumed(ebiten.MouseButtonLeft) || input.MouseButtonConsumed(ebiten.MouseButtonRight) {
+		t.Errorf("only MouseButtonLeft should be consumed")
+	}
+
+	input.ConsumeAll()
+	if !input.KeyConsumed(ebiten.KeyB) || !input.TouchConsumed(1) || !input.AllConsumed() {
+		t.Errorf("all input should be consumed")
+	}
+}
+
+type inputHandlerForTest struct {
+	gameForTest
+	modal    bool
+	handleFn func(input *bamenn.Input)
+}
+
+func (h *inputHandlerForTest) HandleInput(input *bamenn.Input) {
+	h.append(fmt.Sprintf("handleinput:tab=%t", input.KeyConsumed(ebiten.KeyTab)))
+	if h.handleFn != nil {
+		h.handleFn(input)
+	}
+}
+
+func (h *inputHandlerForTest) Modal() bool {
+	return h.modal
+}
@@ -0,0 +1,69 @@
+// Package gameutil provides helpers shared by bamenn and bamennutil.
+package gameutil
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Buffer is a reusable image buffer.
+type Buffer struct {
+	Image *ebiten.Image
+}
+
+// Get returns a cleared image of the specified size. The image is reallocated only when the size changes.
+func (b *Buffer) Get(width, height int) *ebiten.Image {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	if b.Image != nil {
+		if s := b.Image.Bounds().Size(); s.X == width && s.Y == height {
+			b.Image.Clear()
+			return b.Image
+		}
+		b.Image.Deallocate()
+	}
+
+	b.Image = ebiten.NewImage(width, height)
+	return b.Image
+}
+
+// Capture returns a cleared image of the size of src with src drawn on it.
+// The upper-left of src.Bounds() is at (0, 0) of the image even if src is a SubImage, since Ebitengine draws a SubImage source from the upper-left of its bounds.
+// Therefore src must not be translated by -src.Bounds().Min.
+func (b *Buffer) Capture(src *ebiten.Image) *ebiten.Image {
+	size := src.Bounds().Size()
+	img := b.Get(size.X, size.Y)
+	img.DrawImage(src, nil)
+	return img
+}
+
+// Deallocate deallocates the image if it is allocated.
+func (b *Buffer) Deallocate() {
+	if b.Image != nil {
+		b.Image.Deallocate()
+	}
+}
+
+// LayoutF calls ebiten.LayoutFer.LayoutF if g implements it, otherwise ebiten.Game.Layout.
+func LayoutF(g ebiten.Game, outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	if l, ok := g.(ebiten.LayoutFer); ok {
+		return l.LayoutF(outsideWidth, outsideHeight)
+	}
+
+	owi := int(outsideWidth)
+	ohi := int(outsideHeight)
+
+	if owi < 1 {
+		owi = 1
+	}
+	if ohi < 1 {
+		ohi = 1
+	}
+
+	wi, hi := g.Layout(owi, ohi)
+	return float64(wi), float64(hi)
+}
@@ -0,0 +1,118 @@
+package bamenn
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// InterstitialGame is an ebiten.Game run by InterstitialTransition. It signals the end by Completer.
+type InterstitialGame interface {
+	ebiten.Game
+	Completer
+}
+
+// InterstitialTransition is a Transition that runs an ebiten.Game between the previous and next scenes, such as a "Stage 2" card waiting for input.
+// Scenes are switched at the first Update, and the Transition completes when the InterstitialGame is completed.
+// The next scene is not updated until the Transition completes, and its OnArrivaler.OnArrival is called after that.
+// Input routed to Sequence is handled by the InterstitialGame instead of the next scene.
+// The event functions of the InterstitialGame are called as a scene of Sequence.
+type InterstitialTransition struct {
+	game    InterstitialGame
+	state   sceneState
+	updated bool
+	buffer  gameutil.Buffer
+}
+
+// NewInterstitialTransition creates a new InterstitialTransition instance.
+func NewInterstitialTransition(game InterstitialGame) *InterstitialTransition {
+	return &InterstitialTransition{game: game}
+}
+
+// Game returns the InterstitialGame.
+func (t *InterstitialTransition) Game() InterstitialGame {
+	return t.game
+}
+
+// Reset is Transition implementation. It starts the InterstitialGame.
+func (t *InterstitialTransition) Reset() {
+	t.end()
+	t.updated = false
+	if t.state.start() {
+		callIfImpl(t.game, func(o OnStarter) { o.OnStart() })
+	}
+}
+
+// Update is Transition implementation. It updates the InterstitialGame until it is completed.
+func (t *InterstitialTransition) Update() error {
+	if !t.state.started {
+		return nil
+	}
+
+	if t.state.arrive() {
+		callIfImpl(t.game, func(o OnArrivaler) { o.OnArrival() })
+	}
+
+	if err := t.game.Update(); err != nil {
+		return err
+	}
+	t.updated = true
+
+	if t.game.Completed() {
+		t.end()
+	}
+	return nil
+}
+
+// OnEnd is OnEnder implementation. It ends the InterstitialGame if it is running, e.g. when Sequence ends during the Transition.
+func (t *InterstitialTransition) OnEnd() {
+	t.end()
+}
+
+// end ends the InterstitialGame if it is started.
+func (t *InterstitialTransition) end() {
+	if t.state.depart() {
+		callIfImpl(t.game, func(o OnDeparturer) { o.OnDeparture() })
+	}
+	if t.state.end() {
+		callIfImpl(t.game, func(o OnEnder) { o.OnEnd() })
+	}
+}
+
+// Draw is Transition implementation. It draws the InterstitialGame over the screen.
+// If the Layout of the InterstitialGame differs from the screen size, it is scaled by ScalingLetterbox.
+func (t *InterstitialTransition) Draw(screen *ebiten.Image) {
+	if !t.state.started {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	w, h := sceneSize(t.game, float64(size.X), float64(size.Y))
+	if w == size.X && h == size.Y {
+		t.game.Draw(screen)
+		return
+	}
+
+	img := t.buffer.Get(w, h)
+	t.game.Draw(img)
+	ScalingLetterbox.drawScaled(screen, img)
+}
+
+// Completed is Transition implementation. It returns true after the InterstitialGame is completed.
+func (t *InterstitialTransition) Completed() bool {
+	return t.updated && !t.state.started
+}
+
+// HoldsSceneUpdate is SceneUpdateHolder implementation. It returns true while the InterstitialGame is running.
+func (t *InterstitialTransition) HoldsSceneUpdate() bool {
+	return t.state.started
+}
+
+// HandleInput is InputHandler implementation. It routes the input to the InterstitialGame.
+func (t *InterstitialTransition) HandleInput(input *Input) {
+	callIfImpl(t.game, func(h InputHandler) { h.HandleInput(input) })
+}
+
+// CanSwitchScenes is Transition implementation. It returns true after the first Update.
+func (t *InterstitialTransition) CanSwitchScenes() bool {
+	return t.updated
+}
@@ -0,0 +1,133 @@
+package bamenn_test
+
+import (
+	"testing"
+
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestInterstitialTransition(t *testing.T) {
+	r := recorder{}
+
+	s1 := &eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}
+	s2 := &eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }}}
+	card := &interstitialForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "card", Recorder: &r}}}
+	card.UpdateFn = func() error {
+		card.frames++
+		return nil
+	}
+
+	seq := bamenn.NewSequence(s1)
+	tran := bamenn.NewInterstitialTransition(card)
+
+	for i := range 5 {
+		if i == 1 {
+			seq.SwitchWithTransition(s2, tran)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:update",
+		"card:onstart",
+		"s1:ondeparture",
+		"card:onarrival",
+		"card:update",
+		"s1:onend",
+		"s2:onstart",
+		"card:update",
+		"card:update",
+		"card:ondeparture",
+		"card:onend",
+		"s2:onarrival",
+		"s2:update",
+		"s2:update",
+	}, r.Log)
+}
+
+func TestInterstitialTransitionSequenceEnd(t *testing.T) {
+	r := recorder{}
+
+	s1 := &gameForTest{UpdateFn: func() error { return nil }}
+	s2 := &eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }}}
+	card := &interstitialForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "card", Recorder: &r, UpdateFn: func() error { return nil }}}}
+
+	seq := bamenn.NewSequence(s1)
+	seq.SwitchWithTransition(s2, bamenn.NewInterstitialTransition(card))
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+
+	// The card is running when the Sequence ends.
+	seq.OnEnd()
+
+	compareLogs(t, []string{
+		"card:onstart",
+		"card:onarrival",
+		"card:update",
+		"s2:onstart",
+		"card:ondeparture",
+		"card:onend",
+		"s2:onend",
+	}, r.Log)
+}
+
+func TestInterstitialTransitionInput(t *testing.T) {
+	r := recorder{}
+
+	s1 := &gameForTest{UpdateFn: func() error { return nil }}
+	s2 := &inputHandlerForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }}}
+	card := &interstitialInputForTest{inputHandlerForTest: inputHandlerForTest{gameForTest: gameForTest{Name: "card", Recorder: &r}}}
+	card.UpdateFn = func() error {
+		card.frames++
+		return nil
+	}
+
+	seq := bamenn.NewSequence(s1)
+	p := bamenn.NewParallel(seq)
+	p.SetInputRouting(true)
+
+	for i := range 5 {
+		if i == 1 {
+			seq.SwitchWithTransition(s2, bamenn.NewInterstitialTransition(card))
+		}
+		if err := p.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	// The input is routed to the card instead of s2 until the card is completed.
+	compareLogs(t, []string{
+		"card:handleinput:tab=false",
+		"card:update",
+		"card:handleinput:tab=false",
+		"card:update",
+		"card:handleinput:tab=false",
+		"card:update",
+		"s2:update",
+		"s2:handleinput:tab=false",
+		"s2:update",
+	}, r.Log)
+}
+
+type interstitialForTest struct {
+	eventsForTest
+	frames int
+}
+
+func (i *interstitialForTest) Completed() bool {
+	return i.frames >= 3
+}
+
+type interstitialInputForTest struct {
+	inputHandlerForTest
+	frames int
+}
+
+func (i *interstitialInputForTest) Completed() bool {
+	return i.frames >= 3
+}
@@ -0,0 +1,67 @@
+package bamenn
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LayoutSize is a screen size returned by Layout.
+type LayoutSize struct {
+	Width  float64
+	Height float64
+}
+
+// LayoutPolicy chooses the screen size of Parallel from the sizes returned by Layout of its Games.
+// sizes[i] is the size of games[i]. games are ordered from the bottom to the top.
+type LayoutPolicy func(games []ebiten.Game, sizes []LayoutSize) LayoutSize
+
+// ScreenSizeSetter is an interface for a Game of Parallel that is told the screen size chosen by the LayoutPolicy.
+type ScreenSizeSetter interface {
+	// SetScreenSize is called in Layout or LayoutF of Parallel after the screen size is chosen.
+	SetScreenSize(width, height float64)
+}
+
+var (
+	// LayoutPolicyMax chooses the largest width and height. It is the default LayoutPolicy.
+	LayoutPolicyMax LayoutPolicy = func(games []ebiten.Game, sizes []LayoutSize) LayoutSize {
+		size := LayoutSize{}
+		for _, s := range sizes {
+			size.Width = max(size.Width, s.Width)
+			size.Height = max(size.Height, s.Height)
+		}
+		return size
+	}
+
+	// LayoutPolicyMin chooses the smallest width and height.
+	LayoutPolicyMin LayoutPolicy = func(games []ebiten.Game, sizes []LayoutSize) LayoutSize {
+		if len(sizes) == 0 {
+			return LayoutSize{}
+		}
+		size := sizes[0]
+		for _, s := range sizes[1:] {
+			size.Width = min(size.Width, s.Width)
+			size.Height = min(size.Height, s.Height)
+		}
+		return size
+	}
+
+	// LayoutPolicyFirst chooses the size of the first Game, i.e. the bottom one.
+	LayoutPolicyFirst LayoutPolicy = func(games []ebiten.Game, sizes []LayoutSize) LayoutSize {
+		if len(sizes) == 0 {
+			return LayoutSize{}
+		}
+		return sizes[0]
+	}
+)
+
+// LayoutPolicyPrimary returns a LayoutPolicy that chooses the size of the primary Game.
+// If the primary Game is not held by Parallel, LayoutPolicyMax is used.
+func LayoutPolicyPrimary(primary ebiten.Game) LayoutPolicy {
+	return func(games []ebiten.Game, sizes []LayoutSize) LayoutSize {
+		if i := slices.Index(games, primary); i >= 0 {
+			return sizes[i]
+		}
+		return LayoutPolicyMax(games, sizes)
+	}
+}
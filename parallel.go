@@ -1,35 +1,337 @@
 package bamenn
 
 import (
+	"cmp"
+	"context"
 	"errors"
+	"math"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
 )
 
 // Parallel runs multiple Games in parallel.
 type Parallel struct {
-	games []ebiten.Game
-	errs  []error // errs is error cache for Update()
+	games       []ebiten.Game // games are ordered from the bottom to the top.
+	zs          []int         // zs are the z values of games.
+	updateOrder []ebiten.Game // updateOrder is the order set by SetUpdateOrder.
+	updates     []ebiten.Game // updates are games in the order of Update.
+	concurrent  []ebiten.Game // concurrent are games updated concurrently.
+	batches     []int         // batches[i] is the number of consecutive games updated concurrently from updates[i].
+	workers     int           // workers is the maximum number of goroutines for concurrent Update.
+	pool        workerPool    // pool keeps the goroutines for concurrent Update.
+	errs        []error       // errs is error cache for Update()
+	postEffects postEffectChain
+	observer    EventObserver
+	bus         eventBusOwner
+	busScopes   []*EventBus // busScopes are the scopes of the EventBus passed to the Games.
+	routing     bool
+	input       Input
+	layout      LayoutPolicy
+	layoutSizes []LayoutSize  // layoutSizes is cache for Layout()
+	laidOut     []ebiten.Game // laidOut is the Games in the order of layoutSizes.
 }
 
 // NewParallel creates a new Parallel instance.
 func NewParallel(games ...ebiten.Game) *Parallel {
-	return &Parallel{games: games}
+	games = slices.Clone(games)
+	return &Parallel{games: games, zs: make([]int, len(games)), updates: games}
+}
+
+// SetPostEffects sets PostEffects applied to the final screen in order.
+func (p *Parallel) SetPostEffects(effects ...PostEffect) {
+	p.postEffects.effects = effects
+}
+
+// Games returns the ebiten.Games it holds in the order of Draw, from the bottom to the top. The returned slice must not be modified.
+func (p *Parallel) Games() []ebiten.Game {
+	return p.games
+}
+
+// Add adds the Game to the top of the drawing order. Its z value is the same as the current top.
+// Event functions of the Game are not called by Add.
+func (p *Parallel) Add(game ebiten.Game) {
+	z := 0
+	if len(p.zs) > 0 {
+		z = p.zs[len(p.zs)-1]
+	}
+	p.games = append(slices.Clip(p.games), game)
+	p.zs = append(slices.Clip(p.zs), z)
+	p.updateOrderChanged()
+}
+
+// Remove removes the Game. It returns false if the Game is not held.
+// Event functions of the Game are not called by Remove.
+func (p *Parallel) Remove(game ebiten.Game) bool {
+	i := p.index(game)
+	if i < 0 {
+		return false
+	}
+	p.games = slices.Delete(slices.Clone(p.games), i, i+1)
+	p.zs = slices.Delete(slices.Clone(p.zs), i, i+1)
+	p.updateOrder = slices.DeleteFunc(p.updateOrder, func(g ebiten.Game) bool { return g == game })
+	p.concurrent = slices.DeleteFunc(p.concurrent, func(g ebiten.Game) bool { return g == game })
+	p.updateOrderChanged()
+	return true
+}
+
+// BringToFront moves the Game to the top of the drawing order. Its z value becomes the largest one.
+// It returns false if the Game is not held.
+func (p *Parallel) BringToFront(game ebiten.Game) bool {
+	i := p.index(game)
+	if i < 0 {
+		return false
+	}
+	z := slices.Max(p.zs)
+	p.games = append(slices.Delete(slices.Clone(p.games), i, i+1), game)
+	p.zs = append(slices.Delete(slices.Clone(p.zs), i, i+1), z)
+	p.updateOrderChanged()
+	return true
+}
+
+// SendToBack moves the Game to the bottom of the drawing order. Its z value becomes the smallest one.
+// It returns false if the Game is not held.
+func (p *Parallel) SendToBack(game ebiten.Game) bool {
+	i := p.index(game)
+	if i < 0 {
+		return false
+	}
+	z := slices.Min(p.zs)
+	p.games = slices.Insert(slices.Delete(slices.Clone(p.games), i, i+1), 0, game)
+	p.zs = slices.Insert(slices.Delete(slices.Clone(p.zs), i, i+1), 0, z)
+	p.updateOrderChanged()
+	return true
+}
+
+// SetZ sets the z value of the Game. Games are drawn in ascending order of z values, and Games with the same z value keep their order.
+// The z value of each Game is 0 by default. It returns false if the Game is not held.
+func (p *Parallel) SetZ(game ebiten.Game, z int) bool {
+	i := p.index(game)
+	if i < 0 {
+		return false
+	}
+	p.games, p.zs = slices.Clone(p.games), slices.Clone(p.zs)
+	p.zs[i] = z
+
+	type layer struct {
+		game ebiten.Game
+		z    int
+	}
+	layers := make([]layer, len(p.games))
+	for i := range p.games {
+		layers[i] = layer{game: p.games[i], z: p.zs[i]}
+	}
+	slices.SortStableFunc(layers, func(a, b layer) int { return cmp.Compare(a.z, b.z) })
+	for i, l := range layers {
+		p.games[i], p.zs[i] = l.game, l.z
+	}
+
+	p.updateOrderChanged()
+	return true
+}
+
+// Z returns the z value of the Game. It returns false if the Game is not held.
+func (p *Parallel) Z(game ebiten.Game) (int, bool) {
+	i := p.index(game)
+	if i < 0 {
+		return 0, false
+	}
+	return p.zs[i], true
+}
+
+// SetUpdateOrder sets the order of Update independently of the order of Draw.
+// The listed Games are updated first in the order, and then the rest are updated in the order of Draw.
+// For example, UI can be updated first to handle input and be drawn last.
+// If no Game is passed, the order of Update is the same as that of Draw.
+func (p *Parallel) SetUpdateOrder(games ...ebiten.Game) {
+	p.updateOrder = slices.Clone(games)
+	p.updateOrderChanged()
+}
+
+// UpdateOrder returns the ebiten.Games it holds in the order of Update. The returned slice must not be modified.
+func (p *Parallel) UpdateOrder() []ebiten.Game {
+	return p.updates
+}
+
+// SetConcurrent marks the Game to be updated concurrently or not. It returns false if the Game is not held.
+// Consecutive marked Games in the order of Update are updated concurrently on a worker pool, and Update waits for all of them before updating the next Game.
+// Errors are joined in the order of Update as well as the sequential Update.
+// Mark only Games that do not share state with others. The Scheduler and the EventBus passed to them can be used concurrently.
+func (p *Parallel) SetConcurrent(game ebiten.Game, concurrent bool) bool {
+	if p.index(game) < 0 {
+		return false
+	}
+	p.concurrent = slices.DeleteFunc(p.concurrent, func(g ebiten.Game) bool { return g == game })
+	if concurrent {
+		p.concurrent = append(p.concurrent, game)
+	}
+	p.updateOrderChanged()
+	return true
+}
+
+// SetWorkers sets the maximum number of goroutines to update Games concurrently. The default is runtime.GOMAXPROCS(0).
+// The goroutines are started by the first concurrent Update and kept until OnEnd is called.
+func (p *Parallel) SetWorkers(workers int) {
+	p.workers = workers
+}
+
+// updateOrderChanged rebuilds the order of Update.
+func (p *Parallel) updateOrderChanged() {
+	defer p.updateBatches()
+
+	if len(p.updateOrder) == 0 {
+		p.updates = p.games
+		return
+	}
+
+	updates := make([]ebiten.Game, 0, len(p.games))
+	for _, g := range p.updateOrder {
+		if p.index(g) >= 0 && !slices.Contains(updates, g) {
+			updates = append(updates, g)
+		}
+	}
+	for _, g := range p.games {
+		if !slices.Contains(updates, g) {
+			updates = append(updates, g)
+		}
+	}
+	p.updates = updates
+}
+
+// updateBatches rebuilds the batches of Games updated concurrently.
+func (p *Parallel) updateBatches() {
+	p.batches = make([]int, len(p.updates))
+	n := 0
+	for i := len(p.updates) - 1; i >= 0; i-- {
+		if slices.Contains(p.concurrent, p.updates[i]) {
+			n++
+		} else {
+			n = 0
+		}
+		p.batches[i] = n
+	}
+}
+
+// index returns the index of the Game in the order of Draw, or -1 if it is not held.
+func (p *Parallel) index(game ebiten.Game) int {
+	return slices.Index(p.games, game)
+}
+
+// SetEventObserver sets the EventObserver called when an event function is called for the Games it holds.
+func (p *Parallel) SetEventObserver(observer EventObserver) {
+	p.observer = observer
+}
+
+// EventObserver returns the EventObserver set by SetEventObserver.
+func (p *Parallel) EventObserver() EventObserver {
+	return p.observer
+}
+
+// SetEventBus is EventBusSetter implementation.
+// The scopes of bus are passed to all Games implementing EventBusSetter. Events are delivered by the owner of bus.
+func (p *Parallel) SetEventBus(bus *EventBus) {
+	p.bus.set(bus)
+	p.connectEventBus()
+}
+
+// EventBus returns the EventBus whose scopes are passed to the Games implementing EventBusSetter.
+// If no EventBus is set by SetEventBus, it creates a new one and delivers events in Update before updating the Games.
+func (p *Parallel) EventBus() *EventBus {
+	if p.bus.bus == nil {
+		p.bus.get()
+		p.connectEventBus()
+	}
+	return p.bus.bus
+}
+
+// connectEventBus passes new scopes of the EventBus to the Games.
+func (p *Parallel) connectEventBus() {
+	p.closeEventBusScopes()
+	for _, g := range p.games {
+		callIfImpl(g, func(c EventBusSetter) {
+			scope := p.bus.bus.Scope()
+			p.busScopes = append(p.busScopes, scope)
+			c.SetEventBus(scope)
+		})
+	}
+}
+
+// closeEventBusScopes unsubscribes all subscriptions of the Games.
+func (p *Parallel) closeEventBusScopes() {
+	for _, s := range p.busScopes {
+		s.Close()
+	}
+	p.busScopes = p.busScopes[:0]
+}
+
+// SetInputRouting enables or disables the input routing.
+// If it is enabled, InputHandler.HandleInput of the Games are called from the top, i.e. the highest index, to the bottom before ebiten.Game.Update.
+// The Games beneath a Game implementing Modal are not offered input while Modal returns true.
+// Enable it only on the outermost Parallel. A nested Parallel routes the input offered by its parent.
+func (p *Parallel) SetInputRouting(enabled bool) {
+	p.routing = enabled
+}
+
+// InputRouting returns true if the input routing is enabled.
+func (p *Parallel) InputRouting() bool {
+	return p.routing
+}
+
+// HandleInput is InputHandler implementation.
+// It routes the input to the Games from the top to the bottom.
+func (p *Parallel) HandleInput(input *Input) {
+	for i := len(p.games) - 1; i >= 0; i-- {
+		g := p.games[i]
+		callIfImpl(g, func(h InputHandler) { h.HandleInput(input) })
+		if m, ok := asImpl[Modal](g); ok && m.Modal() {
+			return
+		}
+	}
+}
+
+// Modal is Modal implementation. It returns true if any of the Games is modal.
+func (p *Parallel) Modal() bool {
+	for _, g := range p.games {
+		if m, ok := asImpl[Modal](g); ok && m.Modal() {
+			return true
+		}
+	}
+	return false
 }
 
 // Update is ebiten.Game implementation.
-// it calls all ebiten.Game.Update in the order of index. All Updates are called and errors are joined.
+// it calls all ebiten.Game.Update in the order set by SetUpdateOrder, or the order of index by default. All Updates are called and errors are joined.
+// Games marked by SetConcurrent are updated concurrently.
+// Events of the EventBus are delivered and the input is routed before them.
 func (p *Parallel) Update() error {
+	p.EventBus()
+	p.bus.deliver()
+
+	if p.routing {
+		p.input.reset()
+		p.HandleInput(&p.input)
+	}
+
 	if len(p.errs) < len(p.games) {
 		p.errs = make([]error, len(p.games))
 	}
 	p.errs = p.errs[:len(p.games)]
 
-	for i := range p.games {
-		p.errs[i] = p.games[i].Update()
+	// The Games may be reordered, added or removed during Update. It takes effect from the next Update.
+	updates, batches, errs := p.updates, p.batches, p.errs
+	for i := 0; i < len(updates); {
+		if i < len(batches) && batches[i] > 1 {
+			n := batches[i]
+			p.pool.update(updates[i:i+n], errs[i:i+n], p.workers)
+			i += n
+			continue
+		}
+		errs[i] = updates[i].Update()
+		i++
 	}
 
-	return errors.Join(p.errs...)
+	return errors.Join(errs...)
 }
 
 // Draw is ebiten.Game implementation.
@@ -40,26 +342,61 @@ func (p *Parallel) Draw(screen *ebiten.Image) {
 	}
 }
 
+// SetLayoutPolicy sets the LayoutPolicy to choose the screen size from the sizes of the Games. The default is LayoutPolicyMax.
+// The chosen size is told to the Games implementing ScreenSizeSetter.
+func (p *Parallel) SetLayoutPolicy(policy LayoutPolicy) {
+	p.layout = policy
+}
+
 // Layout is ebiten.Game implementation.
-// It returns the largest width and height of all Layouts.
+// It returns the size chosen by the LayoutPolicy from all Layouts. By default, it is the largest width and height.
 func (p *Parallel) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	var maxW, maxH int = 0, 0
+	sizes := p.layoutSizes[:0]
 	for _, g := range p.games {
 		w, h := g.Layout(outsideWidth, outsideHeight)
-		if w > maxW {
-			maxW = w
-		}
-		if h > maxH {
-			maxH = h
-		}
+		sizes = append(sizes, LayoutSize{Width: float64(w), Height: float64(h)})
+	}
+	p.layoutSizes = sizes
+	p.laidOut = p.games
+
+	size := p.chooseLayoutSize(sizes)
+	return int(math.Ceil(size.Width)), int(math.Ceil(size.Height))
+}
+
+// SetScreenSize is ScreenSizeSetter implementation. It tells the size chosen by the parent to all Games.
+func (p *Parallel) SetScreenSize(width, height float64) {
+	for _, g := range p.games {
+		callIfImpl(g, func(s ScreenSizeSetter) { s.SetScreenSize(width, height) })
+	}
+}
+
+// LayoutSize returns the size returned by Layout or LayoutF of the Game at the last Layout or LayoutF of it.
+// It returns false if the Game has not been laid out.
+func (p *Parallel) LayoutSize(game ebiten.Game) (LayoutSize, bool) {
+	i := slices.Index(p.laidOut, game)
+	if i < 0 {
+		return LayoutSize{}, false
+	}
+	return p.layoutSizes[i], true
+}
+
+// chooseLayoutSize chooses the screen size by the LayoutPolicy and tells it to the Games.
+func (p *Parallel) chooseLayoutSize(sizes []LayoutSize) LayoutSize {
+	policy := p.layout
+	if policy == nil {
+		policy = LayoutPolicyMax
 	}
 
-	return maxW, maxH
+	size := policy(p.games, sizes)
+	p.SetScreenSize(size.Width, size.Height)
+	return size
 }
 
 // DrawFinalScreen is ebiten.FinalScreenDrawer implementation.
-// It calls only the DrawFinalScreen of the lowest index that implements FinalScreenDrawer among the Games it holds.
+// It applies PostEffects and calls only the DrawFinalScreen of the lowest index that implements FinalScreenDrawer among the Games it holds.
 func (p *Parallel) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.Image, geoM ebiten.GeoM) {
+	offScreen = p.postEffects.apply(offScreen, nil)
+
 	for _, g := range p.games {
 		if f, ok := g.(ebiten.FinalScreenDrawer); ok {
 			f.DrawFinalScreen(screen, offScreen, geoM)
@@ -71,62 +408,71 @@ func (p *Parallel) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.
 }
 
 // LayoutF is ebiten.LayoutFer implementation.
-// It returns the largest width and height of all LayoutFs.
+// It returns the size chosen by the LayoutPolicy from all LayoutFs. By default, it is the largest width and height.
 func (p *Parallel) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
-	var maxW, maxH float64 = 0, 0
-
-	layoutF := func(g ebiten.Game) (w, h float64) {
-		if l, ok := g.(ebiten.LayoutFer); ok {
-			return l.LayoutF(outsideWidth, outsideHeight)
-		}
-
-		owi := int(outsideWidth)
-		ohi := int(outsideHeight)
+	sizes := p.layoutSizes[:0]
+	for _, g := range p.games {
+		w, h := gameutil.LayoutF(g, outsideWidth, outsideHeight)
+		sizes = append(sizes, LayoutSize{Width: w, Height: h})
+	}
+	p.layoutSizes = sizes
+	p.laidOut = p.games
 
-		if owi < 1 {
-			owi = 1
-		}
-		if ohi < 1 {
-			ohi = 1
-		}
+	size := p.chooseLayoutSize(sizes)
+	return size.Width, size.Height
+}
 
-		wi, hi := g.Layout(owi, ohi)
-		return float64(wi), float64(hi)
+// Assets is AssetDeclarer implementation.
+// It returns the assets of all Games implementing AssetDeclarer in the order of index.
+func (p *Parallel) Assets() []string {
+	var assets []string
+	for _, g := range p.games {
+		callIfImpl(g, func(d AssetDeclarer) { assets = append(assets, d.Assets()...) })
 	}
+	return assets
+}
 
+// SetContext is ContextSetter implementation.
+// it passes ctx to all Games implementing ContextSetter.
+func (p *Parallel) SetContext(ctx context.Context) {
 	for _, g := range p.games {
-		w, h := layoutF(g)
-		if w > maxW {
-			maxW = w
-		}
-		if h > maxH {
-			maxH = h
-		}
+		callIfImpl(g, func(c ContextSetter) { c.SetContext(ctx) })
 	}
+}
 
-	return maxW, maxH
+// SetScheduler is SchedulerSetter implementation.
+// it passes the Scheduler to all Games implementing SchedulerSetter.
+func (p *Parallel) SetScheduler(scheduler *Scheduler) {
+	for _, g := range p.games {
+		callIfImpl(g, func(c SchedulerSetter) { c.SetScheduler(scheduler) })
+	}
 }
 
 // OnStart is OnStarter implementation.
 // it calls all OnStarter.OnStart in the order of index if implemented.
 func (p *Parallel) OnStart() {
 	for _, g := range p.games {
+		p.observer.notify(g, EventStart)
 		callIfImpl(g, func(o OnStarter) { o.OnStart() })
 	}
 }
 
 // OnEnd is OnEnder implementation.
-// it calls all OnEnder.OnEnd in the order of index if implemented.
+// it calls all OnEnder.OnEnd in the order of index if implemented, and then unsubscribes the subscriptions of the Games and stops the goroutines for concurrent Update.
 func (p *Parallel) OnEnd() {
 	for _, g := range p.games {
+		p.observer.notify(g, EventEnd)
 		callIfImpl(g, func(o OnEnder) { o.OnEnd() })
 	}
+	p.closeEventBusScopes()
+	p.pool.stop()
 }
 
 // OnArrival is OnArrivaler implementation.
 // it calls all OnArrivaler.OnArrival in the order of index if implemented.
 func (p *Parallel) OnArrival() {
 	for _, g := range p.games {
+		p.observer.notify(g, EventArrival)
 		callIfImpl(g, func(o OnArrivaler) { o.OnArrival() })
 	}
 }
@@ -135,6 +481,135 @@ func (p *Parallel) OnArrival() {
 // it calls all OnDeparturer.OnDeparture in the order of index if implemented.
 func (p *Parallel) OnDeparture() {
 	for _, g := range p.games {
+		p.observer.notify(g, EventDeparture)
 		callIfImpl(g, func(o OnDeparturer) { o.OnDeparture() })
 	}
 }
+
+// parallelGames is embedded by types that process their Games as Parallel but decide the order and the layout of the Games by themselves, e.g. SplitScreen.
+// It exposes only the methods of Parallel that do not conflict with them.
+type parallelGames struct {
+	parallel *Parallel
+}
+
+// Games returns the ebiten.Games it holds in the order of Draw. The returned slice must not be modified.
+func (p parallelGames) Games() []ebiten.Game {
+	return p.parallel.Games()
+}
+
+// SetUpdateOrder sets the order of Update. See Parallel.SetUpdateOrder.
+func (p parallelGames) SetUpdateOrder(games ...ebiten.Game) {
+	p.parallel.SetUpdateOrder(games...)
+}
+
+// UpdateOrder returns the ebiten.Games it holds in the order of Update. The returned slice must not be modified.
+func (p parallelGames) UpdateOrder() []ebiten.Game {
+	return p.parallel.UpdateOrder()
+}
+
+// SetConcurrent marks the Game to be updated concurrently. See Parallel.SetConcurrent.
+func (p parallelGames) SetConcurrent(game ebiten.Game, concurrent bool) bool {
+	return p.parallel.SetConcurrent(game, concurrent)
+}
+
+// SetWorkers sets the maximum number of goroutines to update Games concurrently. See Parallel.SetWorkers.
+func (p parallelGames) SetWorkers(workers int) {
+	p.parallel.SetWorkers(workers)
+}
+
+// SetPostEffects sets PostEffects applied to the final screen in order.
+func (p parallelGames) SetPostEffects(effects ...PostEffect) {
+	p.parallel.SetPostEffects(effects...)
+}
+
+// SetEventObserver sets the EventObserver called when an event function is called for the Games it holds.
+func (p parallelGames) SetEventObserver(observer EventObserver) {
+	p.parallel.SetEventObserver(observer)
+}
+
+// EventObserver returns the EventObserver set by SetEventObserver.
+func (p parallelGames) EventObserver() EventObserver {
+	return p.parallel.EventObserver()
+}
+
+// SetEventBus is EventBusSetter implementation. See Parallel.SetEventBus.
+func (p parallelGames) SetEventBus(bus *EventBus) {
+	p.parallel.SetEventBus(bus)
+}
+
+// EventBus returns the EventBus whose scopes are passed to the Games implementing EventBusSetter. See Parallel.EventBus.
+func (p parallelGames) EventBus() *EventBus {
+	return p.parallel.EventBus()
+}
+
+// SetInputRouting enables or disables the input routing. See Parallel.SetInputRouting.
+func (p parallelGames) SetInputRouting(enabled bool) {
+	p.parallel.SetInputRouting(enabled)
+}
+
+// InputRouting returns true if the input routing is enabled.
+func (p parallelGames) InputRouting() bool {
+	return p.parallel.InputRouting()
+}
+
+// HandleInput is InputHandler implementation.
+// It routes the input to the Games from the top to the bottom.
+func (p parallelGames) HandleInput(input *Input) {
+	p.parallel.HandleInput(input)
+}
+
+// Modal is Modal implementation. It returns true if any of the Games is modal.
+func (p parallelGames) Modal() bool {
+	return p.parallel.Modal()
+}
+
+// Update is ebiten.Game implementation. See Parallel.Update.
+func (p parallelGames) Update() error {
+	return p.parallel.Update()
+}
+
+// DrawFinalScreen is ebiten.FinalScreenDrawer implementation. See Parallel.DrawFinalScreen.
+func (p parallelGames) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.Image, geoM ebiten.GeoM) {
+	p.parallel.DrawFinalScreen(screen, offScreen, geoM)
+}
+
+// Assets is AssetDeclarer implementation.
+// It returns the assets of all Games implementing AssetDeclarer in the order of index.
+func (p parallelGames) Assets() []string {
+	return p.parallel.Assets()
+}
+
+// SetContext is ContextSetter implementation.
+// it passes ctx to all Games implementing ContextSetter.
+func (p parallelGames) SetContext(ctx context.Context) {
+	p.parallel.SetContext(ctx)
+}
+
+// SetScheduler is SchedulerSetter implementation.
+// it passes the Scheduler to all Games implementing SchedulerSetter.
+func (p parallelGames) SetScheduler(scheduler *Scheduler) {
+	p.parallel.SetScheduler(scheduler)
+}
+
+// OnStart is OnStarter implementation.
+// it calls all OnStarter.OnStart in the order of index if implemented.
+func (p parallelGames) OnStart() {
+	p.parallel.OnStart()
+}
+
+// OnEnd is OnEnder implementation. See Parallel.OnEnd.
+func (p parallelGames) OnEnd() {
+	p.parallel.OnEnd()
+}
+
+// OnArrival is OnArrivaler implementation.
+// it calls all OnArrivaler.OnArrival in the order of index if implemented.
+func (p parallelGames) OnArrival() {
+	p.parallel.OnArrival()
+}
+
+// OnDeparture is OnDeparturer implementation.
+// it calls all OnDeparturer.OnDeparture in the order of index if implemented.
+func (p parallelGames) OnDeparture() {
+	p.parallel.OnDeparture()
+}
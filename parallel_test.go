@@ -3,7 +3,9 @@ package bamenn_test
 import (
 	"errors"
 	"image/color"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/noppikinatta/bamenn"
@@ -244,3 +246,295 @@ func TestParallelDrawFinalScreen(t *testing.T) {
 		})
 	}
 }
+
+func TestParallelOrder(t *testing.T) {
+	r := recorder{}
+	newGame := func(name string) *gameForTest {
+		return &gameForTest{Name: name, Recorder: &r, UpdateFn: func() error { return nil }}
+	}
+	world, hud, menu := newGame("world"), newGame("hud"), newGame("menu")
+
+	p := bamenn.NewParallel(world, hud, menu)
+	run := func() {
+		r.Log = nil
+		p.Update()
+		p.Draw(nil)
+	}
+
+	cases := []struct {
+		Name        string
+		Fn          func()
+		ExpectedLog []string
+	}{
+		{
+			Name:        "default",
+			Fn:          func() {},
+			ExpectedLog: []string{"world:update", "hud:update", "menu:update", "world:draw", "hud:draw", "menu:draw"},
+		},
+		{
+			Name:        "bring-to-front",
+			Fn:          func() { p.BringToFront(hud) },
+			ExpectedLog: []string{"world:update", "menu:update", "hud:update", "world:draw", "menu:draw", "hud:draw"},
+		},
+		{
+			Name:        "send-to-back",
+			Fn:          func() { p.SendToBack(menu) },
+			ExpectedLog: []string{"menu:update", "world:update", "hud:update", "menu:draw", "world:draw", "hud:draw"},
+		},
+		{
+			Name:        "set-z",
+			Fn:          func() { p.SetZ(world, 10) },
+			ExpectedLog: []string{"menu:update", "hud:update", "world:update", "menu:draw", "hud:draw", "world:draw"},
+		},
+		{
+			Name:        "update-order",
+			Fn:          func() { p.SetUpdateOrder(world) },
+			ExpectedLog: []string{"world:update", "menu:update", "hud:update", "menu:draw", "hud:draw", "world:draw"},
+		},
+		{
+			Name:        "update-order-follows-draw-order",
+			Fn:          func() { p.SetZ(menu, 20) },
+			ExpectedLog: []string{"world:update", "hud:update", "menu:update", "hud:draw", "world:draw", "menu:draw"},
+		},
+		{
+			Name:        "reset-update-order",
+			Fn:          func() { p.SetUpdateOrder() },
+			ExpectedLog: []string{"hud:update", "world:update", "menu:update", "hud:draw", "world:draw", "menu:draw"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			c.Fn()
+			run()
+			compareLogs(t, c.ExpectedLog, r.Log)
+		})
+	}
+
+	if z, ok := p.Z(world); !ok || z != 10 {
+		t.Errorf("expected z 10, but got %d", z)
+	}
+	if p.BringToFront(newGame("unknown")) {
+		t.Errorf("BringToFront() should fail with an unknown game")
+	}
+}
+
+func TestParallelReorderDuringUpdate(t *testing.T) {
+	r := recorder{}
+	newGame := func(name string) *gameForTest {
+		return &gameForTest{Name: name, Recorder: &r, UpdateFn: func() error { return nil }}
+	}
+	world, hud, menu := newGame("world"), newGame("hud"), newGame("menu")
+
+	p := bamenn.NewParallel(world, hud, menu)
+	world.UpdateFn = func() error {
+		p.BringToFront(world)
+		p.SetZ(menu, -1)
+		return nil
+	}
+
+	// The new order takes effect from the next Update. Each Game is updated exactly once.
+	p.Update()
+	compareLogs(t, []string{"world:update", "hud:update", "menu:update"}, r.Log)
+
+	r.Log = nil
+	world.UpdateFn = func() error { return nil }
+	p.Update()
+	compareLogs(t, []string{"menu:update", "hud:update", "world:update"}, r.Log)
+}
+
+func TestParallelConcurrent(t *testing.T) {
+	errs := []error{errors.New("err1"), errors.New("err2"), errors.New("err3"), errors.New("err4")}
+
+	var updated [4]bool
+	games := make([]ebiten.Game, len(errs))
+	for i := range games {
+		games[i] = &gameForTest{UpdateFn: func() error {
+			updated[i] = true
+			return errs[i]
+		}}
+	}
+
+	// games[3] checks that the concurrent games are joined before it is updated.
+	games[3].(*gameForTest).UpdateFn = func() error {
+		if !updated[0] || !updated[1] || !updated[2] {
+			t.Errorf("the concurrent games should be updated before the sequential game")
+		}
+		updated[3] = true
+		return errs[3]
+	}
+
+	p := bamenn.NewParallel(games...)
+	p.SetWorkers(2)
+	for _, g := range games[1:3] {
+		p.SetConcurrent(g, true)
+	}
+
+	for range 10 {
+		updated = [4]bool{}
+		err := p.Update()
+		if expected := errors.Join(errs...).Error(); err == nil || err.Error() != expected {
+			t.Fatalf("expected error %q, but got %v", expected, err)
+		}
+		for i, u := range updated {
+			if !u {
+				t.Errorf("games[%d] is not updated", i)
+			}
+		}
+	}
+
+	if p.SetConcurrent(&gameForTest{}, true) {
+		t.Errorf("SetConcurrent() should fail with an unknown game")
+	}
+}
+
+func TestParallelConcurrentWorkers(t *testing.T) {
+	games := make([]ebiten.Game, 4)
+	for i := range games {
+		games[i] = &gameForTest{UpdateFn: func() error { return nil }}
+	}
+
+	p := bamenn.NewParallel(games...)
+	for _, g := range games {
+		p.SetConcurrent(g, true)
+	}
+
+	before := runtime.NumGoroutine()
+	for _, workers := range []int{2, 2, 3, 3} {
+		p.SetWorkers(workers)
+		for range 10 {
+			if err := p.Update(); err != nil {
+				t.Fatalf("unexpected err on Update(): %v", err)
+			}
+		}
+		if n := runtime.NumGoroutine() - before; n > workers {
+			t.Errorf("expected at most %d goroutines for %d workers, but got %d", workers, workers, n)
+		}
+	}
+
+	p.OnEnd()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		runtime.Gosched()
+	}
+	if n := runtime.NumGoroutine() - before; n > 0 {
+		t.Errorf("goroutines should be stopped by OnEnd(), but %d are left", n)
+	}
+
+	// The goroutines are started again after OnEnd.
+	if err := p.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	p.OnEnd()
+}
+
+// TestParallelConcurrentServices is intended to be run with -race.
+func TestParallelConcurrentServices(t *testing.T) {
+	type hit struct{}
+
+	games := make([]ebiten.Game, 4)
+	for i := range games {
+		g := &servicesForTest{}
+		g.UpdateFn = func() error {
+			g.scheduler.After(1, func() {})
+			bamenn.Publish(g.bus, hit{})
+			bamenn.Subscribe(g.bus, func(hit) {}).Unsubscribe()
+			return nil
+		}
+		games[i] = g
+	}
+
+	p := bamenn.NewParallel(games...)
+	for _, g := range games {
+		p.SetConcurrent(g, true)
+	}
+	seq := bamenn.NewSequence(p)
+
+	hits := 0
+	bamenn.Subscribe(seq.EventBus(), func(hit) { hits++ })
+
+	for range 10 {
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	// Events published in an Update are delivered by the next Update.
+	if expected := len(games) * 9; hits != expected {
+		t.Errorf("expected %d events, but got %d", expected, hits)
+	}
+}
+
+type servicesForTest struct {
+	gameForTest
+	scheduler *bamenn.Scheduler
+	bus       *bamenn.EventBus
+}
+
+func (s *servicesForTest) SetScheduler(scheduler *bamenn.Scheduler) {
+	s.scheduler = scheduler
+}
+
+func (s *servicesForTest) SetEventBus(bus *bamenn.EventBus) {
+	s.bus = bus
+}
+
+func TestParallelLayoutPolicy(t *testing.T) {
+	world := &screenSizeSetterForTest{gameForTest: gameForTest{LayoutW: 320, LayoutH: 180}}
+	hud := &screenSizeSetterForTest{gameForTest: gameForTest{LayoutW: 640, LayoutH: 120}}
+	menu := &gameForTest{LayoutW: 480, LayoutH: 360}
+
+	cases := []struct {
+		Name           string
+		Policy         bamenn.LayoutPolicy
+		ExpectedWidth  float64
+		ExpectedHeight float64
+	}{
+		{Name: "default", Policy: nil, ExpectedWidth: 640, ExpectedHeight: 360},
+		{Name: "max", Policy: bamenn.LayoutPolicyMax, ExpectedWidth: 640, ExpectedHeight: 360},
+		{Name: "min", Policy: bamenn.LayoutPolicyMin, ExpectedWidth: 320, ExpectedHeight: 120},
+		{Name: "first", Policy: bamenn.LayoutPolicyFirst, ExpectedWidth: 320, ExpectedHeight: 180},
+		{Name: "primary", Policy: bamenn.LayoutPolicyPrimary(menu), ExpectedWidth: 480, ExpectedHeight: 360},
+		{Name: "primary-not-found", Policy: bamenn.LayoutPolicyPrimary(&gameForTest{}), ExpectedWidth: 640, ExpectedHeight: 360},
+		{
+			Name: "custom",
+			Policy: func(games []ebiten.Game, sizes []bamenn.LayoutSize) bamenn.LayoutSize {
+				return bamenn.LayoutSize{Width: sizes[1].Width, Height: sizes[0].Height}
+			},
+			ExpectedWidth:  640,
+			ExpectedHeight: 180,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			p := bamenn.NewParallel(world, hud, menu)
+			p.SetLayoutPolicy(c.Policy)
+
+			w, h := p.Layout(1, 1)
+			if float64(w) != c.ExpectedWidth || float64(h) != c.ExpectedHeight {
+				t.Errorf("Layout(): expected (%f, %f), but got (%d, %d)", c.ExpectedWidth, c.ExpectedHeight, w, h)
+			}
+
+			wf, hf := p.LayoutF(1, 1)
+			if wf != c.ExpectedWidth || hf != c.ExpectedHeight {
+				t.Errorf("LayoutF(): expected (%f, %f), but got (%f, %f)", c.ExpectedWidth, c.ExpectedHeight, wf, hf)
+			}
+
+			for _, s := range []*screenSizeSetterForTest{world, hud} {
+				if s.width != c.ExpectedWidth || s.height != c.ExpectedHeight {
+					t.Errorf("SetScreenSize(): expected (%f, %f), but got (%f, %f)", c.ExpectedWidth, c.ExpectedHeight, s.width, s.height)
+				}
+			}
+		})
+	}
+}
+
+type screenSizeSetterForTest struct {
+	gameForTest
+	width, height float64
+}
+
+func (s *screenSizeSetterForTest) SetScreenSize(width, height float64) {
+	s.width, s.height = width, height
+}
@@ -0,0 +1,168 @@
+package bamenn
+
+import (
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// Inset is an ebiten.Game drawn in a scaled and positioned rectangle by PictureInPicture.
+// Its fields can be changed at any time.
+type Inset struct {
+	game          ebiten.Game
+	Rect          image.Rectangle // Rect is the rectangle in the screen of the main Game.
+	BorderWidth   int             // BorderWidth is the width of the border drawn outside Rect.
+	BorderColor   color.Color     // BorderColor is the color of the border. The default is white.
+	Opacity       float64         // Opacity is the opacity of the Inset including its border. The default is 1.
+	Hidden        bool            // Hidden hides the Inset. It is still updated.
+	Scaling       ScalingPolicy   // Scaling is how the Game is scaled into Rect. The default is ScalingLetterbox.
+	buffer        gameutil.Buffer
+	width, height int
+}
+
+// Game returns the ebiten.Game of it.
+func (i *Inset) Game() ebiten.Game {
+	return i.game
+}
+
+// draw draws the Game of the Inset onto screen.
+func (i *Inset) draw(screen *ebiten.Image) {
+	if i.Hidden || i.Rect.Empty() || i.Opacity <= 0 {
+		return
+	}
+	origin := screen.Bounds().Min
+	rect := i.Rect.Add(origin)
+
+	if i.BorderWidth > 0 {
+		i.drawBorder(screen, rect)
+	}
+
+	img := i.buffer.Get(i.width, i.height)
+	i.game.Draw(img)
+
+	o := ebiten.DrawImageOptions{}
+	o.GeoM = i.Scaling.GeoM(float64(i.width), float64(i.height), float64(rect.Dx()), float64(rect.Dy()))
+	o.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	o.ColorScale.ScaleAlpha(float32(i.Opacity))
+	o.Filter = i.Scaling.filter()
+	screen.SubImage(rect).(*ebiten.Image).DrawImage(img, &o)
+}
+
+// drawBorder draws the border outside rect.
+func (i *Inset) drawBorder(screen *ebiten.Image, rect image.Rectangle) {
+	outer := rect.Inset(-i.BorderWidth)
+	strips := [...]image.Rectangle{
+		{Min: outer.Min, Max: image.Pt(outer.Max.X, rect.Min.Y)},
+		{Min: image.Pt(outer.Min.X, rect.Max.Y), Max: outer.Max},
+		{Min: image.Pt(outer.Min.X, rect.Min.Y), Max: image.Pt(rect.Min.X, rect.Max.Y)},
+		{Min: image.Pt(rect.Max.X, rect.Min.Y), Max: image.Pt(outer.Max.X, rect.Max.Y)},
+	}
+
+	for _, r := range strips {
+		o := ebiten.DrawImageOptions{}
+		o.GeoM.Scale(float64(r.Dx()), float64(r.Dy()))
+		o.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+		o.ColorScale.ScaleWithColor(i.BorderColor)
+		o.ColorScale.ScaleAlpha(float32(i.Opacity))
+		screen.DrawImage(whitePixel, &o)
+	}
+}
+
+// PictureInPicture draws the main ebiten.Game full-screen and other ebiten.Games in Insets over it, e.g. minimaps and replay previews.
+// Each ebiten.Game has its own Layout. Update and the event functions are processed as Parallel.
+// The Insets are always drawn over the main ebiten.Game in the order of AddInset.
+type PictureInPicture struct {
+	parallelGames
+	main        ebiten.Game
+	mainSize    LayoutSize
+	mainLaidOut bool
+	insets      []*Inset
+}
+
+// NewPictureInPicture creates a new PictureInPicture instance with the main ebiten.Game.
+func NewPictureInPicture(main ebiten.Game) *PictureInPicture {
+	return &PictureInPicture{parallelGames: parallelGames{parallel: NewParallel(main)}, main: main}
+}
+
+// Main returns the main ebiten.Game.
+func (p *PictureInPicture) Main() ebiten.Game {
+	return p.main
+}
+
+// AddInset adds the ebiten.Game as an Inset drawn in rect over the previous Insets.
+func (p *PictureInPicture) AddInset(game ebiten.Game, rect image.Rectangle) *Inset {
+	i := &Inset{
+		game:        game,
+		Rect:        rect,
+		BorderColor: color.White,
+		Opacity:     1,
+		Scaling:     ScalingLetterbox,
+	}
+	p.insets = append(p.insets, i)
+	p.parallel.Add(game)
+	return i
+}
+
+// RemoveInset removes the Inset of the ebiten.Game. It returns false if the ebiten.Game is not an Inset.
+func (p *PictureInPicture) RemoveInset(game ebiten.Game) bool {
+	i := slices.IndexFunc(p.insets, func(i *Inset) bool { return i.game == game })
+	if i < 0 {
+		return false
+	}
+	p.insets[i].buffer.Deallocate()
+	p.insets = slices.Delete(p.insets, i, i+1)
+	p.parallel.Remove(game)
+	return true
+}
+
+// Insets returns the Insets in the order of drawing. The returned slice must not be modified.
+func (p *PictureInPicture) Insets() []*Inset {
+	return p.insets
+}
+
+// Layout is ebiten.Game implementation. It returns the Layout of the main ebiten.Game.
+func (p *PictureInPicture) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	screenWidth, screenHeight = p.main.Layout(outsideWidth, outsideHeight)
+	p.mainSize, p.mainLaidOut = LayoutSize{Width: float64(screenWidth), Height: float64(screenHeight)}, true
+	p.layoutInsets()
+	return screenWidth, screenHeight
+}
+
+// LayoutF is ebiten.LayoutFer implementation. It returns the LayoutF of the main ebiten.Game.
+func (p *PictureInPicture) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	screenWidth, screenHeight = gameutil.LayoutF(p.main, outsideWidth, outsideHeight)
+	p.mainSize, p.mainLaidOut = LayoutSize{Width: screenWidth, Height: screenHeight}, true
+	p.layoutInsets()
+	return screenWidth, screenHeight
+}
+
+// layoutInsets calls the Layouts of the Insets with the sizes of their Rects.
+func (p *PictureInPicture) layoutInsets() {
+	for _, i := range p.insets {
+		i.width, i.height = sceneSize(i.game, float64(i.Rect.Dx()), float64(i.Rect.Dy()))
+	}
+}
+
+// LayoutSize returns the size returned by Layout or LayoutF of the ebiten.Game at the last Layout or LayoutF of it.
+// It returns false if the ebiten.Game has not been laid out.
+func (p *PictureInPicture) LayoutSize(game ebiten.Game) (LayoutSize, bool) {
+	if game == p.main {
+		return p.mainSize, p.mainLaidOut
+	}
+	i := slices.IndexFunc(p.insets, func(i *Inset) bool { return i.game == game })
+	if i < 0 || !p.mainLaidOut {
+		return LayoutSize{}, false
+	}
+	return LayoutSize{Width: float64(p.insets[i].width), Height: float64(p.insets[i].height)}, true
+}
+
+// Draw is ebiten.Game implementation. It draws the main ebiten.Game and then the Insets in order.
+func (p *PictureInPicture) Draw(screen *ebiten.Image) {
+	p.main.Draw(screen)
+	for _, i := range p.insets {
+		i.draw(screen)
+	}
+}
@@ -0,0 +1,65 @@
+package bamenn_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestPictureInPicture(t *testing.T) {
+	r := recorder{}
+
+	main := &gameForTest{Name: "main", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 320, LayoutH: 240}
+	minimap := &viewportGameForTest{gameForTest: gameForTest{Name: "minimap", Recorder: &r, UpdateFn: func() error { return nil }}}
+	replay := &gameForTest{Name: "replay", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 160, LayoutH: 90}
+
+	p := bamenn.NewPictureInPicture(main)
+	p.AddInset(minimap, image.Rect(240, 10, 310, 80))
+	inset := p.AddInset(replay, image.Rect(10, 170, 90, 215))
+	inset.BorderWidth = 2
+	inset.Opacity = 0.5
+
+	w, h := p.Layout(640, 480)
+	if w != 320 || h != 240 {
+		t.Errorf("expected the Layout of the main game (320, 240), but got (%d, %d)", w, h)
+	}
+	if minimap.outsideWidth != 70 || minimap.outsideHeight != 70 {
+		t.Errorf("expected the Layout of the inset with its size (70, 70), but got (%f, %f)", minimap.outsideWidth, minimap.outsideHeight)
+	}
+
+	if err := p.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	p.Draw(ebiten.NewImage(320, 240))
+
+	if minimap.drawnSize != image.Pt(70, 70) {
+		t.Errorf("expected the inset to be drawn with its Layout size, but got %v", minimap.drawnSize)
+	}
+
+	compareLogs(t, []string{
+		"main:layout",
+		"replay:layout",
+		"main:update",
+		"minimap:update",
+		"replay:update",
+		"main:draw",
+		"replay:draw",
+	}, r.Log)
+
+	if size, ok := p.LayoutSize(replay); !ok || size != (bamenn.LayoutSize{Width: 160, Height: 90}) {
+		t.Errorf("expected layout size (160, 90), but got %v", size)
+	}
+	// The Insets are always drawn over the main game, so the z order of Parallel is not exposed.
+	if _, ok := any(p).(interface{ SetZ(ebiten.Game, int) bool }); ok {
+		t.Errorf("PictureInPicture should not expose SetZ")
+	}
+
+	if !p.RemoveInset(replay) || p.RemoveInset(replay) {
+		t.Errorf("RemoveInset() should succeed only once")
+	}
+	if n := len(p.Games()); n != 2 {
+		t.Errorf("expected 2 games, but got %d", n)
+	}
+}
@@ -0,0 +1,73 @@
+package bamenn
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// PostEffect is an interface for a pass applied to the offscreen image before it is drawn to the final screen.
+type PostEffect interface {
+	// Apply draws src onto dst with the effect.
+	// dst is cleared and has the same size as src.
+	Apply(dst, src *ebiten.Image)
+}
+
+// postEffectChain applies PostEffects in order using two buffers alternately.
+type postEffectChain struct {
+	effects []PostEffect
+	buffers [2]gameutil.Buffer
+	applied int
+}
+
+// apply applies all PostEffects and extra to src and returns the result.
+// If there is no PostEffect, src is returned as it is.
+func (c *postEffectChain) apply(src *ebiten.Image, extra PostEffect) *ebiten.Image {
+	c.applied = 0
+
+	for _, e := range c.effects {
+		src = c.applyOne(e, src)
+	}
+	if extra != nil {
+		src = c.applyOne(extra, src)
+	}
+
+	return src
+}
+
+func (c *postEffectChain) applyOne(effect PostEffect, src *ebiten.Image) *ebiten.Image {
+	size := src.Bounds().Size()
+	dst := c.buffers[c.applied%len(c.buffers)].Get(size.X, size.Y)
+	effect.Apply(dst, src)
+	c.applied++
+	return dst
+}
+
+// LinearPostEffect is an interface that applies a post effect as the LinearTransition progresses.
+type LinearPostEffect interface {
+	// Apply draws src onto dst with the effect.
+	// dst is cleared and has the same size as src.
+	Apply(dst, src *ebiten.Image, progress LinearTransitionProgress)
+}
+
+// LinearPostEffectTransition is a LinearTransition that applies a LinearPostEffect to the final screen instead of drawing on the screen.
+type LinearPostEffectTransition struct {
+	*LinearTransition
+	effect LinearPostEffect
+}
+
+// NewLinearPostEffectTransition returns a new LinearPostEffectTransition.
+func NewLinearPostEffectTransition(frameToSwitch, maxFrames int, effect LinearPostEffect) *LinearPostEffectTransition {
+	return &LinearPostEffectTransition{
+		LinearTransition: NewLinearTransition(frameToSwitch, maxFrames, nopLinearTransitionDrawer{}),
+		effect:           effect,
+	}
+}
+
+// Apply is PostEffect implementation.
+func (t *LinearPostEffectTransition) Apply(dst, src *ebiten.Image) {
+	t.effect.Apply(dst, src, t.Progress())
+}
+
+type nopLinearTransitionDrawer struct{}
+
+func (d nopLinearTransitionDrawer) Draw(*ebiten.Image, LinearTransitionProgress) {}
@@ -0,0 +1,103 @@
+package bamenn_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestSequencePostEffects(t *testing.T) {
+	r := recorder{}
+
+	e1 := postEffectForTest{Name: "e1", Recorder: &r}
+	e2 := postEffectForTest{Name: "e2", Recorder: &r}
+
+	var received *ebiten.Image
+	s := finalScreenDrawerForTest{
+		gameForTest: gameForTest{Name: "s", Recorder: &r},
+		drawFn: func(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+			received = offscreen
+		},
+	}
+
+	seq := bamenn.NewSequence(&s)
+	seq.SetPostEffects(&e1, &e2)
+
+	offscreen := ebiten.NewImage(3, 3)
+	seq.DrawFinalScreen(ebiten.NewImage(3, 3), offscreen, ebiten.GeoM{})
+
+	compareLogs(t, []string{
+		"e1:apply",
+		"e2:apply",
+		"s:drawfinalscreen",
+	}, r.Log)
+
+	if e1.src != offscreen {
+		t.Errorf("the first PostEffect should receive the offscreen")
+	}
+	if e2.src != e1.dst {
+		t.Errorf("the second PostEffect should receive the result of the first one")
+	}
+	if received != e2.dst {
+		t.Errorf("DrawFinalScreen should receive the result of the last PostEffect")
+	}
+}
+
+func TestSequenceTransitionPostEffect(t *testing.T) {
+	r := recorder{}
+
+	s1 := gameForTest{Name: "s1"}
+	s2 := gameForTest{Name: "s2"}
+
+	seq := bamenn.NewSequence(&s1)
+	seq.SetPostEffects(&postEffectForTest{Name: "e", Recorder: &r})
+
+	tran := bamenn.NewLinearPostEffectTransition(1, 3, &linearPostEffectForTest{Recorder: &r})
+
+	s1.UpdateFn = func() error {
+		seq.SwitchWithTransition(&s2, tran)
+		return nil
+	}
+	s2.UpdateFn = func() error { return nil }
+
+	screen := ebiten.NewImage(3, 3)
+	finalScreen := ebiten.NewImage(3, 3)
+	for range 5 {
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Game.Update(): %v", err)
+		}
+		seq.DrawFinalScreen(finalScreen, screen, ebiten.GeoM{})
+	}
+
+	compareLogs(t, []string{
+		"e:apply",
+		"t:apply 0",
+		"e:apply",
+		"t:apply 1",
+		"e:apply",
+		"t:apply 2",
+		"e:apply",
+		"e:apply",
+	}, r.Log)
+}
+
+type postEffectForTest struct {
+	Name     string
+	Recorder *recorder
+	dst, src *ebiten.Image
+}
+
+func (e *postEffectForTest) Apply(dst, src *ebiten.Image) {
+	e.Recorder.Append(e.Name, "apply")
+	e.dst, e.src = dst, src
+}
+
+type linearPostEffectForTest struct {
+	Recorder *recorder
+}
+
+func (e *linearPostEffectForTest) Apply(dst, src *ebiten.Image, progress bamenn.LinearTransitionProgress) {
+	e.Recorder.Append("t", fmt.Sprintf("apply %d", progress.CurrentFrame))
+}
@@ -0,0 +1,85 @@
+package bamenn
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// ScalingPolicy represents how an image is fitted into a destination of a different size.
+type ScalingPolicy int
+
+const (
+	// ScalingNone draws without scaling. It is the default of Sequence.
+	ScalingNone ScalingPolicy = iota
+	// ScalingLetterbox scales keeping the aspect ratio and centers the result.
+	ScalingLetterbox
+	// ScalingStretch scales to fill the destination ignoring the aspect ratio.
+	ScalingStretch
+	// ScalingInteger scales by the largest integer factor that fits and centers the result at integer offsets.
+	// If the source is larger than the destination, it behaves like ScalingLetterbox.
+	ScalingInteger
+)
+
+// GeoM returns the ebiten.GeoM to draw a source of srcWidth x srcHeight onto a destination of dstWidth x dstHeight.
+func (p ScalingPolicy) GeoM(srcWidth, srcHeight, dstWidth, dstHeight float64) ebiten.GeoM {
+	var g ebiten.GeoM
+	if srcWidth <= 0 || srcHeight <= 0 {
+		return g
+	}
+
+	sx := dstWidth / srcWidth
+	sy := dstHeight / srcHeight
+
+	switch p {
+	case ScalingNone:
+		return g
+	case ScalingStretch:
+		g.Scale(sx, sy)
+		return g
+	case ScalingInteger:
+		s := math.Min(sx, sy)
+		if s >= 1 {
+			// The offsets are floored as well so that the pixels are aligned to the destination.
+			s = math.Floor(s)
+			g.Scale(s, s)
+			g.Translate(math.Floor((dstWidth-srcWidth*s)/2), math.Floor((dstHeight-srcHeight*s)/2))
+			return g
+		}
+		sx, sy = s, s
+	default:
+		s := math.Min(sx, sy)
+		sx, sy = s, s
+	}
+
+	g.Scale(sx, sy)
+	g.Translate((dstWidth-srcWidth*sx)/2, (dstHeight-srcHeight*sy)/2)
+	return g
+}
+
+// filter returns the ebiten.Filter suitable for the policy.
+func (p ScalingPolicy) filter() ebiten.Filter {
+	if p == ScalingInteger {
+		return ebiten.FilterNearest
+	}
+	return ebiten.FilterLinear
+}
+
+// drawScaled draws src onto dst according to the policy.
+func (p ScalingPolicy) drawScaled(dst, src *ebiten.Image) {
+	sb := src.Bounds()
+	db := dst.Bounds()
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM = p.GeoM(float64(sb.Dx()), float64(sb.Dy()), float64(db.Dx()), float64(db.Dy()))
+	op.GeoM.Translate(float64(db.Min.X), float64(db.Min.Y))
+	op.Filter = p.filter()
+	dst.DrawImage(src, op)
+}
+
+// sceneSize returns the integer screen size of g for the outside size.
+func sceneSize(g ebiten.Game, outsideWidth, outsideHeight float64) (width, height int) {
+	w, h := gameutil.LayoutF(g, outsideWidth, outsideHeight)
+	return int(math.Ceil(w)), int(math.Ceil(h))
+}
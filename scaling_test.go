@@ -0,0 +1,66 @@
+package bamenn_test
+
+import (
+	"testing"
+
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestScalingPolicyGeoM(t *testing.T) {
+	cases := []struct {
+		Name                   string
+		Policy                 bamenn.ScalingPolicy
+		SrcW, SrcH, DstW, DstH float64
+		ExpectedMinX           float64
+		ExpectedMinY           float64
+		ExpectedMaxX           float64
+		ExpectedMaxY           float64
+	}{
+		{
+			Name:   "none",
+			Policy: bamenn.ScalingNone,
+			SrcW:   10, SrcH: 10, DstW: 30, DstH: 20,
+			ExpectedMinX: 0, ExpectedMinY: 0, ExpectedMaxX: 10, ExpectedMaxY: 10,
+		},
+		{
+			Name:   "letterbox",
+			Policy: bamenn.ScalingLetterbox,
+			SrcW:   10, SrcH: 10, DstW: 30, DstH: 20,
+			ExpectedMinX: 5, ExpectedMinY: 0, ExpectedMaxX: 25, ExpectedMaxY: 20,
+		},
+		{
+			Name:   "stretch",
+			Policy: bamenn.ScalingStretch,
+			SrcW:   10, SrcH: 10, DstW: 30, DstH: 20,
+			ExpectedMinX: 0, ExpectedMinY: 0, ExpectedMaxX: 30, ExpectedMaxY: 20,
+		},
+		{
+			Name:   "integer",
+			Policy: bamenn.ScalingInteger,
+			SrcW:   10, SrcH: 10, DstW: 35, DstH: 25,
+			ExpectedMinX: 7, ExpectedMinY: 2, ExpectedMaxX: 27, ExpectedMaxY: 22,
+		},
+		{
+			Name:   "integer-shrink",
+			Policy: bamenn.ScalingInteger,
+			SrcW:   20, SrcH: 20, DstW: 10, DstH: 5,
+			ExpectedMinX: 2.5, ExpectedMinY: 0, ExpectedMaxX: 7.5, ExpectedMaxY: 5,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := c.Policy.GeoM(c.SrcW, c.SrcH, c.DstW, c.DstH)
+
+			minX, minY := g.Apply(0, 0)
+			maxX, maxY := g.Apply(c.SrcW, c.SrcH)
+
+			if minX != c.ExpectedMinX || minY != c.ExpectedMinY {
+				t.Errorf("min expected (%f,%f), but got (%f,%f)", c.ExpectedMinX, c.ExpectedMinY, minX, minY)
+			}
+			if maxX != c.ExpectedMaxX || maxY != c.ExpectedMaxY {
+				t.Errorf("max expected (%f,%f), but got (%f,%f)", c.ExpectedMaxX, c.ExpectedMaxY, maxX, maxY)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package bamenn
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"context"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
 
 // OnStarter is an interface that executes processing at the start of a scene.
 type OnStarter interface {
@@ -24,8 +28,145 @@ type OnDeparturer interface {
 	OnDeparture()
 }
 
+// ContextSetter is an interface for a scene that receives a context.Context scoped to its lifetime.
+type ContextSetter interface {
+	// SetContext is called just before OnStarter.OnStart. ctx is cancelled just before OnEnder.OnEnd.
+	// It is useful to tear down goroutines started by the scene.
+	SetContext(ctx context.Context)
+}
+
+// Event represents an event function called for a scene.
+type Event int
+
+const (
+	// EventStart represents OnStarter.OnStart.
+	EventStart Event = iota
+	// EventArrival represents OnArrivaler.OnArrival.
+	EventArrival
+	// EventDeparture represents OnDeparturer.OnDeparture.
+	EventDeparture
+	// EventEnd represents OnEnder.OnEnd.
+	EventEnd
+)
+
+// String returns the name of the event function.
+func (e Event) String() string {
+	switch e {
+	case EventStart:
+		return "OnStart"
+	case EventArrival:
+		return "OnArrival"
+	case EventDeparture:
+		return "OnDeparture"
+	case EventEnd:
+		return "OnEnd"
+	default:
+		return "Unknown"
+	}
+}
+
+// EventObserver is a function called when an event function is called for a scene, regardless of whether the scene implements it.
+type EventObserver func(scene ebiten.Game, event Event)
+
+// notify calls o if it is not nil.
+func (o EventObserver) notify(scene ebiten.Game, event Event) {
+	if o != nil {
+		o(scene, event)
+	}
+}
+
+// Completer is an interface for a scene that reports the completion of its own flow.
+type Completer interface {
+	// Completed returns true when the flow of the scene is complete.
+	Completed() bool
+}
+
+// sceneState tracks events called for a scene to call each of them exactly once.
+type sceneState struct {
+	started            bool
+	arrived            bool
+	departed           bool
+	completionNotified bool
+	assets             []string
+	cancel             context.CancelFunc
+	scheduler          *Scheduler
+	bus                *EventBus
+}
+
+// start returns true if the scene can start.
+func (s *sceneState) start() bool {
+	if s.started {
+		return false
+	}
+	*s = sceneState{started: true}
+	return true
+}
+
+// arrive returns true if the scene can arrive.
+func (s *sceneState) arrive() bool {
+	if !s.started || s.arrived || s.departed {
+		return false
+	}
+	s.arrived = true
+	return true
+}
+
+// depart returns true if the scene can depart.
+func (s *sceneState) depart() bool {
+	if !s.started || s.departed {
+		return false
+	}
+	s.departed = true
+	return true
+}
+
+// active returns true if the scene is arrived and not departed.
+func (s *sceneState) active() bool {
+	return s.started && s.arrived && !s.departed
+}
+
+// end returns true if the scene can end. The context, the Tasks and the subscriptions of the scene are cancelled.
+func (s *sceneState) end() bool {
+	if !s.started {
+		return false
+	}
+	s.started = false
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.scheduler.CancelAll()
+	s.bus.Close()
+	return true
+}
+
+// Wrapper is an interface for an ebiten.Game that wraps another ebiten.Game, e.g. FixedStep.
+// If a Wrapper does not implement an interface such as OnStarter, Sequence and Parallel look it up in the wrapped ebiten.Game,
+// so a Wrapper implements only the interfaces that it handles by itself.
+type Wrapper interface {
+	// Unwrap returns the wrapped ebiten.Game.
+	Unwrap() ebiten.Game
+}
+
+// asImpl returns g as T. If g does not implement T, the ebiten.Games wrapped by g are looked up through Wrapper.
+func asImpl[T any](g ebiten.Game) (T, bool) {
+	for g != nil {
+		if t, ok := g.(T); ok {
+			return t, true
+		}
+		w, ok := g.(Wrapper)
+		if !ok {
+			break
+		}
+		g = w.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
 func callIfImpl[T any](g ebiten.Game, fn func(t T)) {
-	if t, ok := g.(T); ok {
+	if t, ok := asImpl[T](g); ok {
 		fn(t)
 	}
 }
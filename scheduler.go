@@ -0,0 +1,190 @@
+package bamenn
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SchedulerSetter is an interface for a scene that receives a Scheduler scoped to its lifetime.
+type SchedulerSetter interface {
+	// SetScheduler is called just before OnStarter.OnStart.
+	// Sequence updates the Scheduler just before each ebiten.Game.Update of the scene while it is active, i.e. from OnArrivaler.OnArrival to OnDeparturer.OnDeparture.
+	// All Tasks of the Scheduler are cancelled at OnEnder.OnEnd.
+	SetScheduler(scheduler *Scheduler)
+}
+
+// Task is a function scheduled by Scheduler.
+type Task struct {
+	frame  int
+	update func(frame int) bool // update returns true when the Task is done.
+	done   bool
+}
+
+// Cancel cancels the Task. It is not called after that.
+func (t *Task) Cancel() {
+	t.done = true
+}
+
+// Done returns true if the Task is completed or cancelled.
+func (t *Task) Done() bool {
+	return t.done
+}
+
+// Scheduler runs delayed, repeated and tweening Tasks frame by frame.
+// Tasks can be scheduled from multiple goroutines, such as Games updated concurrently by Parallel.
+type Scheduler struct {
+	mu     sync.Mutex // mu guards tasks.
+	tasks  []*Task
+	paused atomic.Bool
+}
+
+// NewScheduler creates a new Scheduler instance.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// After schedules fn to be called once at the frames-th Update after the call. frames is at least 1.
+func (s *Scheduler) After(frames int, fn func()) *Task {
+	frames = max(frames, 1)
+	return s.add(func(frame int) bool {
+		if frame < frames {
+			return false
+		}
+		fn()
+		return true
+	})
+}
+
+// AfterDuration schedules fn to be called once after the duration. The duration is converted to frames with ebiten.TPS.
+func (s *Scheduler) AfterDuration(d time.Duration, fn func()) *Task {
+	return s.After(durationToFrames(d), fn)
+}
+
+// Every schedules fn to be called every frames Updates until the Task is cancelled. frames is at least 1.
+func (s *Scheduler) Every(frames int, fn func()) *Task {
+	frames = max(frames, 1)
+	return s.add(func(frame int) bool {
+		if frame%frames == 0 {
+			fn()
+		}
+		return false
+	})
+}
+
+// EveryDuration schedules fn to be called every duration until the Task is cancelled. The duration is converted to frames with ebiten.TPS.
+func (s *Scheduler) EveryDuration(d time.Duration, fn func()) *Task {
+	return s.Every(durationToFrames(d), fn)
+}
+
+// Tween changes a value from from to to over frames Updates with the Easing. fn is called with the value immediately and at each Update.
+func (s *Scheduler) Tween(from, to float64, frames int, easing Easing, fn func(value float64)) *Task {
+	frames = max(frames, 1)
+	fn(from)
+	return s.add(func(frame int) bool {
+		rate := easing.Apply(float64(frame) / float64(frames))
+		fn(from + (to-from)*rate)
+		return frame >= frames
+	})
+}
+
+// TweenDuration changes a value from from to to over the duration with the Easing. The duration is converted to frames with ebiten.TPS.
+func (s *Scheduler) TweenDuration(from, to float64, d time.Duration, easing Easing, fn func(value float64)) *Task {
+	return s.Tween(from, to, durationToFrames(d), easing, fn)
+}
+
+func (s *Scheduler) add(update func(frame int) bool) *Task {
+	t := &Task{update: update}
+	s.mu.Lock()
+	s.tasks = append(s.tasks, t)
+	s.mu.Unlock()
+	return t
+}
+
+// Update advances all Tasks by a frame unless it is paused. Tasks scheduled in Update start from the next Update.
+func (s *Scheduler) Update() {
+	if s == nil || s.paused.Load() {
+		return
+	}
+
+	// Tasks added by the callbacks wait for the next Update, and the callbacks may replace s.tasks by CancelAll.
+	s.mu.Lock()
+	tasks := s.tasks
+	s.mu.Unlock()
+
+	for _, t := range tasks {
+		if t.done {
+			continue
+		}
+		t.frame++
+		if t.update(t.frame) {
+			t.done = true
+		}
+	}
+
+	s.mu.Lock()
+	s.tasks = deleteDoneTasks(s.tasks)
+	s.mu.Unlock()
+}
+
+func deleteDoneTasks(tasks []*Task) []*Task {
+	alive := tasks[:0]
+	for _, t := range tasks {
+		if !t.done {
+			alive = append(alive, t)
+		}
+	}
+	clear(tasks[len(alive):])
+	return alive
+}
+
+// SetPaused pauses or resumes it.
+func (s *Scheduler) SetPaused(paused bool) {
+	s.paused.Store(paused)
+}
+
+// Paused returns true if it is paused.
+func (s *Scheduler) Paused() bool {
+	return s.paused.Load()
+}
+
+// Len returns the number of Tasks that are not done.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, t := range s.tasks {
+		if !t.done {
+			n++
+		}
+	}
+	return n
+}
+
+// CancelAll cancels all Tasks.
+func (s *Scheduler) CancelAll() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tasks {
+		t.Cancel()
+	}
+	s.tasks = nil
+}
+
+// durationToFrames converts the duration to the number of frames with ebiten.TPS.
+// 60 is used as TPS if ebiten.SyncWithFPS is set.
+func durationToFrames(d time.Duration) int {
+	tps := ebiten.TPS()
+	if tps <= 0 {
+		tps = 60
+	}
+	return int(math.Ceil(d.Seconds() * float64(tps)))
+}
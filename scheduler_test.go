@@ -0,0 +1,176 @@
+package bamenn_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestScheduler(t *testing.T) {
+	r := recorder{}
+	s := bamenn.NewScheduler()
+
+	s.After(2, func() { r.Append("after", "2") })
+	every := s.Every(2, func() { r.Append("every", "2") })
+	s.Tween(0, 10, 4, bamenn.EaseLinear, func(v float64) { r.Append("tween", fmt.Sprint(v)) })
+	cancelled := s.After(1, func() { r.Append("cancelled", "1") })
+	cancelled.Cancel()
+
+	for i := range 6 {
+		r.Append("frame", fmt.Sprint(i+1))
+		if i == 4 {
+			every.Cancel()
+		}
+		s.Update()
+	}
+
+	compareLogs(t, []string{
+		"tween:0",
+		"frame:1",
+		"tween:2.5",
+		"frame:2",
+		"after:2",
+		"every:2",
+		"tween:5",
+		"frame:3",
+		"tween:7.5",
+		"frame:4",
+		"every:2",
+		"tween:10",
+		"frame:5",
+		"frame:6",
+	}, r.Log)
+
+	if n := s.Len(); n != 0 {
+		t.Errorf("expected no tasks, but got %d", n)
+	}
+}
+
+func TestSchedulerCancelInCallback(t *testing.T) {
+	r := recorder{}
+	s := bamenn.NewScheduler()
+
+	s.After(1, func() {
+		r.Append("after", "1")
+		s.CancelAll()
+		s.After(1, func() { r.Append("added", "1") })
+	})
+	s.After(1, func() { r.Append("cancelled", "1") })
+
+	for i := range 2 {
+		r.Append("frame", fmt.Sprint(i+1))
+		s.Update()
+	}
+
+	compareLogs(t, []string{
+		"frame:1",
+		"after:1",
+		"frame:2",
+		"added:1",
+	}, r.Log)
+
+	if n := s.Len(); n != 0 {
+		t.Errorf("expected no tasks, but got %d", n)
+	}
+}
+
+func TestSchedulerPaused(t *testing.T) {
+	s := bamenn.NewScheduler()
+	called := false
+	task := s.After(1, func() { called = true })
+
+	s.SetPaused(true)
+	s.Update()
+	if called {
+		t.Errorf("the task should not be called while paused")
+	}
+
+	s.SetPaused(false)
+	s.Update()
+	if !called || !task.Done() {
+		t.Errorf("the task should be called after resumed")
+	}
+}
+
+func TestSequenceScheduler(t *testing.T) {
+	r := recorder{}
+
+	s1 := &schedulerSetterForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}}
+	s2 := &eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }}}
+	s1.onStartFn = func() {
+		s1.scheduler.Every(1, func() { r.Append("s1", "task") })
+	}
+
+	seq := bamenn.NewSequence(s1)
+	tran := &transitionForTest{SwitchFrames: 2, MaxFrames: 3}
+
+	for i := range 4 {
+		if i == 1 {
+			seq.SwitchWithTransition(s2, tran)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+	}
+
+	compareLogs(t, []string{
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:task",
+		"s1:update",
+		"s1:ondeparture",
+		"s1:update", // the scheduler is paused after departure.
+		"s1:onend",
+		"s2:onstart",
+		"s2:update",
+		"s2:onarrival",
+		"s2:update",
+	}, r.Log)
+
+	if n := s1.scheduler.Len(); n != 0 {
+		t.Errorf("expected the tasks to be cancelled, but got %d", n)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	easings := []bamenn.Easing{
+		nil,
+		bamenn.EaseLinear,
+		bamenn.EaseInQuad,
+		bamenn.EaseOutQuad,
+		bamenn.EaseInOutQuad,
+		bamenn.EaseInCubic,
+		bamenn.EaseOutCubic,
+		bamenn.EaseInOutCubic,
+		bamenn.EaseInSine,
+		bamenn.EaseOutSine,
+		bamenn.EaseInOutSine,
+	}
+
+	for i, e := range easings {
+		for _, c := range []struct{ in, out float64 }{{-1, 0}, {0, 0}, {1, 1}, {2, 1}} {
+			if v := e.Apply(c.in); math.Abs(v-c.out) > 1e-9 {
+				t.Errorf("%d: Apply(%f) should be %f, but got %f", i, c.in, c.out, v)
+			}
+		}
+	}
+}
+
+type schedulerSetterForTest struct {
+	eventsForTest
+	scheduler *bamenn.Scheduler
+	onStartFn func()
+}
+
+func (s *schedulerSetterForTest) SetScheduler(scheduler *bamenn.Scheduler) {
+	s.scheduler = scheduler
+}
+
+func (s *schedulerSetterForTest) OnStart() {
+	s.eventsForTest.OnStart()
+	if s.onStartFn != nil {
+		s.onStartFn()
+	}
+}
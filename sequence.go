@@ -1,14 +1,39 @@
 package bamenn
 
 import (
+	"context"
+	"errors"
+	"math"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
 )
 
 // Sequence provides the ability to run multiple ebiten.Games in sequence.
 type Sequence struct {
 	current           ebiten.Game
 	transitionUpdater *transitionUpdater
-	onStartCalled     bool
+	state             sceneState
+	completed         bool
+	onComplete        func(child ebiten.Game)
+	observer          EventObserver
+	assets            *AssetManager
+	cache             *SceneCache
+	ctx               context.Context // ctx is the parent of the contexts of scenes.
+	bus               eventBusOwner
+	err               error // err occurred outside of Update is returned by the next Update.
+	scaling           ScalingPolicy
+	outsideWidth      float64
+	outsideHeight     float64
+	layoutWidth       float64
+	layoutHeight      float64
+	buffer            gameutil.Buffer
+	freezeFrame       bool
+	frozen            bool // frozen is true while the current scene is replaced by the freeze frame.
+	freeze            gameutil.Buffer
+	freezeImage       *ebiten.Image
+	composite         gameutil.Buffer
+	postEffects       postEffectChain
 }
 
 // NewSequence creates a new Sequence instance with the first scene.
@@ -22,34 +47,207 @@ func (s *Sequence) SetFirst(first ebiten.Game) {
 	s.current = first
 }
 
+// SetScalingPolicy sets how scenes are drawn when their Layout differs from the screen size.
+// With a policy other than ScalingNone, each scene is drawn into an offscreen image of its own Layout size and scaled onto the screen.
+// The screen size is also kept unchanged while a Transition is being processed, so the previous and next scenes share a common output.
+func (s *Sequence) SetScalingPolicy(policy ScalingPolicy) {
+	s.scaling = policy
+}
+
+// SetFreezeFrame enables or disables the freeze frame.
+// If it is enabled, SwitchWithTransition with a Transition implementing FreezeFrameSetter captures the last frame of the current scene,
+// and the scene is no longer updated nor drawn. The captured image is drawn instead until scenes are switched, and is passed to the Transition.
+// Other Transitions do not use the freeze frame. The freeze frame is always used for a Transition implementing SceneCompositor.
+func (s *Sequence) SetFreezeFrame(enabled bool) {
+	s.freezeFrame = enabled
+}
+
+// SetPostEffects sets PostEffects applied to the final screen in order, regardless of the current scene.
+// While a Transition is processed, the Transition is also applied after them if it implements PostEffect.
+func (s *Sequence) SetPostEffects(effects ...PostEffect) {
+	s.postEffects.effects = effects
+}
+
+// SetAssetManager sets the AssetManager to load assets declared by scenes implementing AssetDeclarer.
+// An error in loading is returned by the next Update and the scene is not started.
+// Returning the error from ebiten.Game.Update terminates the game. If the caller of Update ignores the error, e.g. a parent scene, loading is retried by the next Update.
+func (s *Sequence) SetAssetManager(assets *AssetManager) {
+	s.assets = assets
+}
+
+// SetSceneCache sets the SceneCache used by SwitchByKey.
+// The least recently used scenes in the SceneCache are evicted after scenes are switched.
+func (s *Sequence) SetSceneCache(cache *SceneCache) {
+	s.cache = cache
+}
+
+// SceneCache returns the SceneCache set by SetSceneCache. It returns nil if it is not set.
+func (s *Sequence) SceneCache() *SceneCache {
+	return s.cache
+}
+
+// SetContext is ContextSetter implementation.
+// ctx is the parent of the contexts passed to scenes implementing ContextSetter. The default is context.Background().
+func (s *Sequence) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// SetEventBus is EventBusSetter implementation.
+// The scopes of bus are passed to scenes implementing EventBusSetter. Events are delivered by the owner of bus.
+func (s *Sequence) SetEventBus(bus *EventBus) {
+	s.bus.set(bus)
+}
+
+// EventBus returns the EventBus whose scopes are passed to scenes implementing EventBusSetter.
+// If no EventBus is set by SetEventBus, it creates a new one and delivers events in Update after the current scene starts and before the scenes are updated.
+func (s *Sequence) EventBus() *EventBus {
+	return s.bus.get()
+}
+
 // Update is ebiten.Game implementation.
 func (s *Sequence) Update() error {
+	if !s.state.started {
+		s.startCurrent()
+		if s.inTransition() && !s.transitionUpdater.switched {
+			s.departCurrent()
+		} else {
+			s.arriveCurrent()
+		}
+	}
+
+	s.bus.deliver()
+
 	if s.inTransition() {
 		if err := s.transitionUpdater.Update(); err != nil {
 			return err
 		}
 	}
 
-	if !s.onStartCalled {
-		s.OnStart()
-		s.OnArrival()
-		s.onStartCalled = true
+	if err := s.err; err != nil {
+		s.err = nil
+		return err
+	}
+
+	if s.state.active() {
+		s.state.scheduler.Update()
+	}
+
+	if s.frozen || s.sceneHeld() {
+		return nil
+	}
+
+	if err := s.current.Update(); err != nil {
+		return err
 	}
 
-	return s.current.Update()
+	s.notifyCompletion()
+	return nil
 }
 
 // Draw is ebiten.Game implementation.
 func (s *Sequence) Draw(screen *ebiten.Image) {
-	s.current.Draw(screen)
+	if c, ok := s.compositor(); ok {
+		s.drawComposited(c, screen)
+	} else if s.frozen {
+		if s.freezeImage == nil {
+			size := screen.Bounds().Size()
+			s.captureFreezeFrame(size.X, size.Y)
+		}
+		o := ebiten.DrawImageOptions{}
+		o.GeoM.Translate(float64(screen.Bounds().Min.X), float64(screen.Bounds().Min.Y))
+		screen.DrawImage(s.freezeImage, &o)
+	} else {
+		s.drawScene(s.current, screen)
+	}
 	if s.inTransition() {
 		s.transitionUpdater.Draw(screen)
 	}
 }
 
+// sceneHeld returns true if the Transition being processed holds back Update of the current scene.
+func (s *Sequence) sceneHeld() bool {
+	if !s.inTransition() {
+		return false
+	}
+	h, ok := s.transitionUpdater.transition.(SceneUpdateHolder)
+	return ok && h.HoldsSceneUpdate()
+}
+
+// compositor returns the Transition being processed if it is a SceneCompositor.
+func (s *Sequence) compositor() (SceneCompositor, bool) {
+	if !s.inTransition() {
+		return nil, false
+	}
+	c, ok := s.transitionUpdater.transition.(SceneCompositor)
+	return c, ok
+}
+
+// drawComposited draws the freeze frame of the previous scene and the current scene with the GeoMs of the SceneCompositor.
+func (s *Sequence) drawComposited(c SceneCompositor, screen *ebiten.Image) {
+	bounds := screen.Bounds()
+	if s.frozen && s.freezeImage == nil {
+		s.captureFreezeFrame(bounds.Dx(), bounds.Dy())
+	}
+	previous, next := c.SceneGeoMs(float64(bounds.Dx()), float64(bounds.Dy()))
+
+	if s.freezeImage != nil {
+		o := ebiten.DrawImageOptions{}
+		o.GeoM = previous
+		o.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+		screen.DrawImage(s.freezeImage, &o)
+	}
+	if s.frozen {
+		return
+	}
+
+	img := s.composite.Get(bounds.Dx(), bounds.Dy())
+	s.drawScene(s.current, img)
+	o := ebiten.DrawImageOptions{}
+	o.GeoM = next
+	o.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+	screen.DrawImage(img, &o)
+}
+
+// drawScene draws g onto screen according to the ScalingPolicy.
+func (s *Sequence) drawScene(g ebiten.Game, screen *ebiten.Image) {
+	if s.scaling == ScalingNone {
+		g.Draw(screen)
+		return
+	}
+
+	w, h := sceneSize(g, s.outsideWidth, s.outsideHeight)
+	if size := screen.Bounds().Size(); size.X == w && size.Y == h {
+		g.Draw(screen)
+		return
+	}
+
+	img := s.buffer.Get(w, h)
+	g.Draw(img)
+	s.scaling.drawScaled(screen, img)
+}
+
 // Layout is ebiten.Game implementation.
 func (s *Sequence) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return s.current.Layout(outsideWidth, outsideHeight)
+	s.outsideWidth, s.outsideHeight = float64(outsideWidth), float64(outsideHeight)
+	if w, h, ok := s.transitionOutputSize(); ok {
+		return int(math.Ceil(w)), int(math.Ceil(h))
+	}
+
+	w, h := s.current.Layout(outsideWidth, outsideHeight)
+	s.layoutWidth, s.layoutHeight = float64(w), float64(h)
+	return w, h
+}
+
+// transitionOutputSize returns the screen size shared by scenes during the Transition.
+func (s *Sequence) transitionOutputSize() (width, height float64, ok bool) {
+	if s.scaling == ScalingNone || !s.inTransition() {
+		return 0, 0, false
+	}
+	t := s.transitionUpdater
+	if t.outputWidth <= 0 || t.outputHeight <= 0 {
+		return 0, 0, false
+	}
+	return t.outputWidth, t.outputHeight, true
 }
 
 // Switch switches the ebiten.Game to run in Sequence.
@@ -63,12 +261,129 @@ func (s *Sequence) SwitchWithTransition(next ebiten.Game, transition Transition)
 		return false
 	}
 	p := newTransitionUpdater(s, next, transition)
+	p.outputWidth, p.outputHeight = s.layoutWidth, s.layoutHeight
 	s.transitionUpdater = p
 	transition.Reset()
-	callIfImpl(s.current, func(o OnDeparturer) { o.OnDeparture() })
+	s.departCurrent()
+
+	if s.usesFreezeFrame(transition) {
+		s.frozen = true
+		if s.layoutWidth > 0 && s.layoutHeight > 0 {
+			s.captureFreezeFrame(int(math.Ceil(s.layoutWidth)), int(math.Ceil(s.layoutHeight)))
+		}
+	}
 	return true
 }
 
+// usesFreezeFrame returns true if the freeze frame is captured for the Transition.
+func (s *Sequence) usesFreezeFrame(transition Transition) bool {
+	if _, ok := transition.(SceneCompositor); ok {
+		return true
+	}
+	_, ok := transition.(FreezeFrameSetter)
+	return ok && s.freezeFrame
+}
+
+// captureFreezeFrame draws the current scene into the freeze frame and passes it to the Transition.
+func (s *Sequence) captureFreezeFrame(width, height int) {
+	img := s.freeze.Get(width, height)
+	s.drawScene(s.current, img)
+	s.freezeImage = img
+	if f, ok := s.transitionUpdater.transition.(FreezeFrameSetter); ok {
+		f.SetFreezeFrame(img)
+	}
+}
+
+// releaseFreezeFrame stops using the freeze frame. The image is kept to be reused.
+func (s *Sequence) releaseFreezeFrame() {
+	s.frozen = false
+	if s.freezeImage == nil {
+		return
+	}
+	s.freezeImage = nil
+	if !s.inTransition() {
+		return
+	}
+	if f, ok := s.transitionUpdater.transition.(FreezeFrameSetter); ok {
+		f.SetFreezeFrame(nil)
+	}
+}
+
+// SwitchByKey switches to the scene of the key in the SceneCache with the Transition.
+// It returns false without error if the Transition is being processed.
+func (s *Sequence) SwitchByKey(key string, transition Transition) (bool, error) {
+	if s.cache == nil {
+		return false, errors.New("bamenn: SceneCache is not set")
+	}
+	if s.inTransition() {
+		return false, nil
+	}
+
+	next, err := s.cache.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return s.SwitchWithTransition(next, transition), nil
+}
+
+// HandleInput is InputHandler implementation. It routes the input to the current scene.
+// While the Transition holds back Update of the current scene, the input is routed to the Transition instead.
+func (s *Sequence) HandleInput(input *Input) {
+	if s.sceneHeld() {
+		if h, ok := s.transitionUpdater.transition.(InputHandler); ok {
+			h.HandleInput(input)
+		}
+		return
+	}
+	callIfImpl(s.current, func(h InputHandler) { h.HandleInput(input) })
+}
+
+// Modal is Modal implementation. It returns true if the current scene is modal.
+func (s *Sequence) Modal() bool {
+	m, ok := asImpl[Modal](s.current)
+	return ok && m.Modal()
+}
+
+// SetScreenSize is ScreenSizeSetter implementation. It tells the size to the current scene.
+func (s *Sequence) SetScreenSize(width, height float64) {
+	callIfImpl(s.current, func(c ScreenSizeSetter) { c.SetScreenSize(width, height) })
+}
+
+// Current returns the current scene.
+func (s *Sequence) Current() ebiten.Game {
+	return s.current
+}
+
+// Transition returns the Transition being processed. It returns nil if there is no Transition in process.
+func (s *Sequence) Transition() Transition {
+	if !s.inTransition() {
+		return nil
+	}
+	return s.transitionUpdater.transition
+}
+
+// TransitionPhase returns the phase of the Transition being processed.
+func (s *Sequence) TransitionPhase() TransitionPhase {
+	switch {
+	case !s.inTransition():
+		return TransitionPhaseNone
+	case s.transitionUpdater.switched:
+		return TransitionPhaseAfterSwitch
+	default:
+		return TransitionPhaseBeforeSwitch
+	}
+}
+
+// SetEventObserver sets the EventObserver called when an event function is called for the scenes.
+func (s *Sequence) SetEventObserver(observer EventObserver) {
+	s.observer = observer
+}
+
+// EventObserver returns the EventObserver set by SetEventObserver.
+func (s *Sequence) EventObserver() EventObserver {
+	return s.observer
+}
+
 // inTransition returns true if the Transition is being processed.
 func (s *Sequence) inTransition() bool {
 	return s.transitionUpdater != nil
@@ -76,19 +391,160 @@ func (s *Sequence) inTransition() bool {
 
 // switchScenes switches scenes.
 func (s *Sequence) switchScenes(next ebiten.Game) {
-	callIfImpl(s.current, func(o OnEnder) { o.OnEnd() })
+	// The assets of the previous scene are released after the next scene acquires its assets, so that shared assets are not reloaded.
+	assets := s.endScene()
+	s.frozen = false
 	s.current = next
-	callIfImpl(s.current, func(o OnStarter) { o.OnStart() })
+	s.startCurrent()
+	s.assets.Release(assets)
+
+	if s.cache != nil {
+		s.cache.trim(s.current)
+	}
 }
 
 // endTransition is called when the Transition completed.
 func (s *Sequence) endTransition() {
+	s.releaseFreezeFrame()
 	s.transitionUpdater = nil
+	s.arriveCurrent()
+}
+
+// startCurrent calls OnStarter.OnStart of the current scene if it is not started.
+func (s *Sequence) startCurrent() {
+	if !s.state.start() {
+		return
+	}
+	if !s.acquireAssets() {
+		// The scene is left pending and started again by the next Update.
+		s.state.started = false
+		return
+	}
+	s.setContext()
+	s.setScheduler()
+	s.setEventBus()
+	s.observer.notify(s.current, EventStart)
+	callIfImpl(s.current, func(o OnStarter) { o.OnStart() })
+}
+
+// arriveCurrent calls OnArrivaler.OnArrival of the current scene once after it starts.
+// It is deferred until the Transition completes.
+func (s *Sequence) arriveCurrent() {
+	if s.inTransition() || !s.state.arrive() {
+		return
+	}
+	s.observer.notify(s.current, EventArrival)
 	callIfImpl(s.current, func(o OnArrivaler) { o.OnArrival() })
 }
 
+// departCurrent calls OnDeparturer.OnDeparture of the current scene once after it starts.
+func (s *Sequence) departCurrent() {
+	if !s.state.depart() {
+		return
+	}
+	s.observer.notify(s.current, EventDeparture)
+	callIfImpl(s.current, func(o OnDeparturer) { o.OnDeparture() })
+}
+
+// endCurrent ends the current scene and releases its assets.
+func (s *Sequence) endCurrent() {
+	s.assets.Release(s.endScene())
+}
+
+// endScene cancels the context, the Tasks and the subscriptions of the current scene and calls OnEnder.OnEnd if it is started, and returns the assets to release.
+func (s *Sequence) endScene() (assets []string) {
+	if !s.state.end() {
+		return nil
+	}
+	s.observer.notify(s.current, EventEnd)
+	callIfImpl(s.current, func(o OnEnder) { o.OnEnd() })
+	return s.state.assets
+}
+
+// setContext creates the context of the current scene and passes it if the scene implements ContextSetter.
+func (s *Sequence) setContext() {
+	c, ok := asImpl[ContextSetter](s.current)
+	if !ok {
+		return
+	}
+
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	s.state.cancel = cancel
+	c.SetContext(ctx)
+}
+
+// setScheduler passes a new Scheduler if the current scene implements SchedulerSetter.
+func (s *Sequence) setScheduler() {
+	callIfImpl(s.current, func(c SchedulerSetter) {
+		s.state.scheduler = NewScheduler()
+		c.SetScheduler(s.state.scheduler)
+	})
+}
+
+// setEventBus passes a scope of the EventBus if the current scene implements EventBusSetter.
+func (s *Sequence) setEventBus() {
+	callIfImpl(s.current, func(c EventBusSetter) {
+		s.state.bus = s.bus.get().Scope()
+		c.SetEventBus(s.state.bus)
+	})
+}
+
+// acquireAssets loads the assets declared by the current scene. It returns false if loading fails.
+func (s *Sequence) acquireAssets() bool {
+	d, ok := asImpl[AssetDeclarer](s.current)
+	if !ok || s.assets == nil {
+		return true
+	}
+
+	names := d.Assets()
+	if err := s.assets.Acquire(names); err != nil {
+		s.err = errors.Join(s.err, err)
+		return false
+	}
+	s.state.assets = names
+	return true
+}
+
+// notifyCompletion calls the function set by SetOnComplete once when the current scene completes.
+func (s *Sequence) notifyCompletion() {
+	if s.onComplete == nil || s.state.completionNotified {
+		return
+	}
+	c, ok := asImpl[Completer](s.current)
+	if !ok || !c.Completed() {
+		return
+	}
+	s.state.completionNotified = true
+	s.onComplete(s.current)
+}
+
+// Complete signals that the flow of it is complete.
+// If it is a scene of another Sequence, the function set by SetOnComplete of the Sequence is called.
+// The completion is reset when OnStart is called.
+func (s *Sequence) Complete() {
+	s.completed = true
+}
+
+// Completed is Completer implementation.
+// It returns true after Complete is called.
+func (s *Sequence) Completed() bool {
+	return s.completed
+}
+
+// SetOnComplete sets the function called once when the current scene, which implements Completer, completes.
+// It is checked after each ebiten.Game.Update of the current scene, and is useful to switch from a nested Sequence.
+func (s *Sequence) SetOnComplete(fn func(child ebiten.Game)) {
+	s.onComplete = fn
+}
+
 // DrawFinalScreen is ebiten.FinalScreenDrawer implementation.
 func (s *Sequence) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.Image, geoM ebiten.GeoM) {
+	offScreen = s.postEffects.apply(offScreen, s.transitionPostEffect())
+
 	if f, ok := s.current.(ebiten.FinalScreenDrawer); ok {
 		f.DrawFinalScreen(screen, offScreen, geoM)
 	} else {
@@ -96,43 +552,55 @@ func (s *Sequence) DrawFinalScreen(screen ebiten.FinalScreen, offScreen *ebiten.
 	}
 }
 
-// LayoutF is ebiten.LayoutFer implementation.
-func (s *Sequence) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
-	if l, ok := s.current.(ebiten.LayoutFer); ok {
-		return l.LayoutF(outsideWidth, outsideHeight)
+// transitionPostEffect returns the Transition in process if it implements PostEffect.
+func (s *Sequence) transitionPostEffect() PostEffect {
+	if !s.inTransition() {
+		return nil
 	}
-
-	owi := int(outsideWidth)
-	ohi := int(outsideHeight)
-
-	if owi < 1 {
-		owi = 1
+	if e, ok := s.transitionUpdater.transition.(PostEffect); ok {
+		return e
 	}
-	if ohi < 1 {
-		ohi = 1
+	return nil
+}
+
+// LayoutF is ebiten.LayoutFer implementation.
+func (s *Sequence) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	s.outsideWidth, s.outsideHeight = outsideWidth, outsideHeight
+	if w, h, ok := s.transitionOutputSize(); ok {
+		return w, h
 	}
 
-	wi, hi := s.current.Layout(owi, ohi)
-	return float64(wi), float64(hi)
+	w, h := gameutil.LayoutF(s.current, outsideWidth, outsideHeight)
+	s.layoutWidth, s.layoutHeight = w, h
+	return w, h
 }
 
 // OnStart is OnStarter implementation.
+// It resets the completion and starts the current scene. The events of the current scene are called exactly once even if it is nested.
 func (s *Sequence) OnStart() {
-	callIfImpl(s.current, func(o OnStarter) { o.OnStart() })
-	s.onStartCalled = true
+	s.completed = false
+	s.startCurrent()
 }
 
 // OnEnd is OnEnder implementation.
+// The Transition in process is discarded after OnEnder.OnEnd of it is called if it implements OnEnder.
 func (s *Sequence) OnEnd() {
-	callIfImpl(s.current, func(o OnEnder) { o.OnEnd() })
+	s.releaseFreezeFrame()
+	if s.transitionUpdater != nil {
+		if o, ok := s.transitionUpdater.transition.(OnEnder); ok {
+			o.OnEnd()
+		}
+	}
+	s.transitionUpdater = nil
+	s.endCurrent()
 }
 
 // OnArrival is OnArrivaler implementation.
 func (s *Sequence) OnArrival() {
-	callIfImpl(s.current, func(o OnArrivaler) { o.OnArrival() })
+	s.arriveCurrent()
 }
 
 // OnDeparture is OnDeparturer implementation.
 func (s *Sequence) OnDeparture() {
-	callIfImpl(s.current, func(o OnDeparturer) { o.OnDeparture() })
+	s.departCurrent()
 }
@@ -1,6 +1,8 @@
 package bamenn_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -302,6 +304,135 @@ func TestSequence(t *testing.T) {
 				"s3:update",
 			},
 		},
+		{
+			Name: "nested-sequence-as-first",
+			GameFn: func() (*bamenn.Sequence, *recorder) {
+				r := recorder{}
+
+				s11 := eventsForTest{gameForTest: gameForTest{Name: "s11", Recorder: &r}}
+				seq1 := bamenn.NewSequence(&s11)
+				s2 := eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r}}
+
+				seq := bamenn.NewSequence(seq1)
+
+				s11.UpdateFn = func() error {
+					seq.Switch(&s2)
+					return nil
+				}
+
+				return seq, &r
+			},
+			ExpectedLog: []string{
+				"s11:layout",
+				"s11:onstart",
+				"s11:onarrival",
+				"s11:update",
+				"s11:ondeparture",
+				"s11:draw",
+				"s11:layout",
+				"s11:onend",
+				"s2:onstart",
+				"s2:onarrival",
+				"s2:update",
+			},
+		},
+		{
+			Name: "switch-before-first-update",
+			GameFn: func() (*bamenn.Sequence, *recorder) {
+				r := recorder{}
+
+				s1 := eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r}}
+				s2 := eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r}}
+
+				seq := bamenn.NewSequence(&s1)
+				tran := transitionForTest{Name: "t1", SwitchFrames: 1, MaxFrames: 2, Recorder: &r}
+				seq.SwitchWithTransition(&s2, &tran)
+
+				s2count := 0
+				s2.UpdateFn = func() error {
+					if s2count < 1 {
+						s2count++
+						return nil
+					}
+					return ebiten.Termination
+				}
+
+				return seq, &r
+			},
+			ExpectedLog: []string{
+				"t1:reset",
+				"s1:layout",
+				"s1:onstart",
+				"s1:ondeparture",
+				"t1:update",
+				"s1:onend",
+				"s2:onstart",
+				"s2:update",
+				"s2:draw",
+				"t1:draw",
+				"s2:layout",
+				"t1:update",
+				"s2:onarrival",
+				"s2:update",
+			},
+		},
+		{
+			Name: "nested-sequence-completion",
+			GameFn: func() (*bamenn.Sequence, *recorder) {
+				r := recorder{}
+
+				s1 := eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r}}
+				s21 := eventsForTest{gameForTest: gameForTest{Name: "s21", Recorder: &r}}
+				seq2 := bamenn.NewSequence(&s21)
+				s3 := eventsForTest{gameForTest: gameForTest{Name: "s3", Recorder: &r}}
+
+				seq := bamenn.NewSequence(&s1)
+				seq.SetOnComplete(func(child ebiten.Game) {
+					if child != seq2 {
+						return
+					}
+					r.Append("seq", "oncomplete")
+					seq.Switch(&s3)
+				})
+
+				s1.UpdateFn = func() error {
+					seq.Switch(seq2)
+					return nil
+				}
+
+				s21.UpdateFn = func() error {
+					seq2.Complete()
+					return nil
+				}
+
+				s3.UpdateFn = func() error {
+					return ebiten.Termination
+				}
+
+				return seq, &r
+			},
+			ExpectedLog: []string{
+				"s1:layout",
+				"s1:onstart",
+				"s1:onarrival",
+				"s1:update",
+				"s1:ondeparture",
+				"s1:draw",
+				"s1:layout",
+				"s1:onend",
+				"s21:onstart",
+				"s21:onarrival",
+				"s21:update",
+				"seq:oncomplete",
+				"s21:ondeparture",
+				"s21:draw",
+				"s21:layout",
+				"s21:onend",
+				"s3:onstart",
+				"s3:onarrival",
+				"s3:update",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -357,3 +488,197 @@ func TestSequenceLayoutFReturnValue(t *testing.T) {
 		})
 	}
 }
+
+func TestSequenceScalingPolicy(t *testing.T) {
+	r := recorder{}
+
+	s1 := gameForTest{Name: "s1", LayoutW: 4, LayoutH: 4}
+	s2 := gameForTest{Name: "s2", LayoutW: 2, LayoutH: 1}
+
+	seq := bamenn.NewSequence(&s1)
+	seq.SetScalingPolicy(bamenn.ScalingLetterbox)
+
+	tran := transitionForTest{Name: "t1", SwitchFrames: 1, MaxFrames: 3}
+
+	recordSize := func(name string) func(screen *ebiten.Image) {
+		return func(screen *ebiten.Image) {
+			s := screen.Bounds().Size()
+			r.Append(name, fmt.Sprintf("%dx%d", s.X, s.Y))
+		}
+	}
+	s1.DrawFn = recordSize("s1")
+	s2.DrawFn = recordSize("s2")
+
+	s1.UpdateFn = func() error {
+		seq.SwitchWithTransition(&s2, &tran)
+		return nil
+	}
+	s2.UpdateFn = func() error { return nil }
+
+	for range 5 {
+		w, h := seq.Layout(10, 10)
+		r.Append("seq", fmt.Sprintf("layout %dx%d", w, h))
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Game.Update(): %v", err)
+		}
+		seq.Draw(ebiten.NewImage(w, h))
+	}
+
+	compareLogs(t, []string{
+		"seq:layout 4x4",
+		"s1:4x4",
+		"seq:layout 4x4",
+		"s2:2x1",
+		"seq:layout 4x4",
+		"s2:2x1",
+		"seq:layout 4x4",
+		"s2:2x1",
+		"seq:layout 2x1",
+		"s2:2x1",
+	}, r.Log)
+}
+
+func TestSequenceContext(t *testing.T) {
+	r := recorder{}
+	s1 := &contextSetterForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }}}}
+	s2 := &contextSetterForTest{eventsForTest: eventsForTest{gameForTest: gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }}}}
+
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seq := bamenn.NewSequence(s1)
+	seq.SetContext(parent)
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if s1.ctx.Err() != nil {
+		t.Errorf("the context of s1 should not be cancelled before switching")
+	}
+
+	seq.Switch(s2)
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	if s1.ctx.Err() == nil {
+		t.Errorf("the context of s1 should be cancelled after switching")
+	}
+	if s2.ctx.Err() != nil {
+		t.Errorf("the context of s2 should not be cancelled")
+	}
+
+	cancel()
+	if s2.ctx.Err() == nil {
+		t.Errorf("the context of s2 should be cancelled with the parent")
+	}
+
+	compareLogs(t, []string{
+		"s1:setcontext",
+		"s1:onstart",
+		"s1:onarrival",
+		"s1:update",
+		"s1:ondeparture",
+		"s1:cancelled:true",
+		"s1:onend",
+		"s2:setcontext",
+		"s2:onstart",
+		"s2:onarrival",
+		"s2:update",
+	}, r.Log)
+}
+
+type contextSetterForTest struct {
+	eventsForTest
+	ctx context.Context
+}
+
+func (c *contextSetterForTest) SetContext(ctx context.Context) {
+	c.append("setcontext")
+	c.ctx = ctx
+}
+
+func (c *contextSetterForTest) OnEnd() {
+	c.append(fmt.Sprintf("cancelled:%t", c.ctx.Err() != nil))
+	c.eventsForTest.OnEnd()
+}
+
+func TestSequenceFreezeFrame(t *testing.T) {
+	r := recorder{}
+
+	s1 := &gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	s2 := &gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	tran := &freezeFrameTransitionForTest{transitionForTest: transitionForTest{Name: "t", Recorder: &r, SwitchFrames: 2, MaxFrames: 3}}
+
+	seq := bamenn.NewSequence(s1)
+	seq.SetFreezeFrame(true)
+	screen := ebiten.NewImage(3, 3)
+
+	for i := range 5 {
+		seq.Layout(3, 3)
+		if i == 1 {
+			seq.SwitchWithTransition(s2, tran)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		seq.Draw(screen)
+	}
+
+	compareLogs(t, []string{
+		"s1:layout",
+		"s1:update",
+		"s1:draw",
+		"s1:layout",
+		"t:reset",
+		"s1:draw", // captured
+		"t:setfreezeframe:true",
+		"t:update",
+		"t:draw",
+		"s1:layout",
+		"t:update",
+		"s2:update",
+		"s2:draw",
+		"t:draw",
+		"s2:layout",
+		"t:update",
+		"t:setfreezeframe:false",
+		"s2:update",
+		"s2:draw",
+		"s2:layout",
+		"s2:update",
+		"s2:draw",
+	}, r.Log)
+}
+
+func TestSequenceFreezeFrameWithoutSetter(t *testing.T) {
+	r := recorder{}
+
+	s1 := &gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	s2 := &gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	tran := &transitionForTest{Name: "t", SwitchFrames: 2, MaxFrames: 3}
+
+	seq := bamenn.NewSequence(s1)
+	seq.SetFreezeFrame(true)
+	screen := ebiten.NewImage(3, 3)
+
+	seq.Layout(3, 3)
+	seq.SwitchWithTransition(s2, tran)
+	if err := seq.Update(); err != nil {
+		t.Fatalf("unexpected err on Update(): %v", err)
+	}
+	seq.Draw(screen)
+
+	// The Transition does not implement FreezeFrameSetter, so the previous scene is still updated and drawn.
+	compareLogs(t, []string{
+		"s1:layout",
+		"s1:update",
+		"s1:draw",
+	}, r.Log)
+}
+
+type freezeFrameTransitionForTest struct {
+	transitionForTest
+}
+
+func (f *freezeFrameTransitionForTest) SetFreezeFrame(img *ebiten.Image) {
+	f.append(fmt.Sprintf("setfreezeframe:%t", img != nil))
+}
@@ -0,0 +1,105 @@
+package bamenn
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SlideEdge is the edge of the screen from which the next scene slides in.
+type SlideEdge int
+
+const (
+	// SlideFromRight slides the next scene in from the right edge, and the previous scene out to the left.
+	SlideFromRight SlideEdge = iota
+	// SlideFromLeft slides the next scene in from the left edge, and the previous scene out to the right.
+	SlideFromLeft
+	// SlideFromBottom slides the next scene in from the bottom edge, and the previous scene out to the top.
+	SlideFromBottom
+	// SlideFromTop slides the next scene in from the top edge, and the previous scene out to the bottom.
+	SlideFromTop
+)
+
+// direction returns the unit vector of the position where the next scene starts.
+func (e SlideEdge) direction() (x, y float64) {
+	switch e {
+	case SlideFromLeft:
+		return -1, 0
+	case SlideFromBottom:
+		return 0, 1
+	case SlideFromTop:
+		return 0, -1
+	default:
+		return 1, 0
+	}
+}
+
+// SlideTransition is a Transition that pushes the previous scene out while the next scene slides in from the SlideEdge.
+// It implements SceneCompositor, so Sequence draws both scenes moved by it.
+// Scenes are switched at the first Update, so the next scene is updated while it slides in.
+type SlideTransition struct {
+	currentFrame int
+	maxFrames    int
+	edge         SlideEdge
+	easing       Easing
+	parallax     float64
+}
+
+// NewSlideTransition creates a new SlideTransition instance that slides for maxFrames frames.
+func NewSlideTransition(maxFrames int, edge SlideEdge) *SlideTransition {
+	return &SlideTransition{maxFrames: maxFrames, edge: edge, parallax: 1}
+}
+
+// SetEasing sets the Easing of the movement. The default is linear.
+func (t *SlideTransition) SetEasing(easing Easing) {
+	t.easing = easing
+}
+
+// SetParallax sets the rate of the movement of the previous scene to the next scene.
+// 1 pushes the previous scene out together, which is the default. 0 keeps the previous scene still and the next scene slides over it.
+// A value between them moves the previous scene slower like a parallax.
+func (t *SlideTransition) SetParallax(rate float64) {
+	t.parallax = rate
+}
+
+// Reset is Transition implementation.
+func (t *SlideTransition) Reset() {
+	t.currentFrame = 0
+}
+
+// Update is Transition implementation.
+func (t *SlideTransition) Update() error {
+	if t.Completed() {
+		return nil
+	}
+	t.currentFrame++
+	return nil
+}
+
+// Draw is Transition implementation. It draws nothing because Sequence draws the scenes with SceneGeoMs.
+func (t *SlideTransition) Draw(screen *ebiten.Image) {}
+
+// Completed is Transition implementation.
+func (t *SlideTransition) Completed() bool {
+	return t.currentFrame >= t.maxFrames
+}
+
+// CanSwitchScenes is Transition implementation. It always returns true.
+func (t *SlideTransition) CanSwitchScenes() bool {
+	return true
+}
+
+// Rate returns the eased progress rate in the range of 0.0~1.0.
+func (t *SlideTransition) Rate() float64 {
+	if t.maxFrames <= 0 {
+		return 1
+	}
+	return t.easing.Apply(float64(t.currentFrame) / float64(t.maxFrames))
+}
+
+// SceneGeoMs is SceneCompositor implementation.
+func (t *SlideTransition) SceneGeoMs(width, height float64) (previous, next ebiten.GeoM) {
+	rate := t.Rate()
+	dx, dy := t.edge.direction()
+	previous.Translate(-dx*width*rate*t.parallax, -dy*height*rate*t.parallax)
+	next.Translate(dx*width*(1-rate), dy*height*(1-rate))
+	return previous, next
+}
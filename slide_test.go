@@ -0,0 +1,107 @@
+package bamenn_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestSlideTransitionSceneGeoMs(t *testing.T) {
+	cases := []struct {
+		Name                       string
+		Edge                       bamenn.SlideEdge
+		Parallax                   float64
+		Frames                     int
+		PrevX, PrevY, NextX, NextY float64
+	}{
+		{Name: "right-start", Edge: bamenn.SlideFromRight, Parallax: 1, Frames: 0, PrevX: 0, PrevY: 0, NextX: 40, NextY: 0},
+		{Name: "right-half", Edge: bamenn.SlideFromRight, Parallax: 1, Frames: 2, PrevX: -20, PrevY: 0, NextX: 20, NextY: 0},
+		{Name: "left-half", Edge: bamenn.SlideFromLeft, Parallax: 1, Frames: 2, PrevX: 20, PrevY: 0, NextX: -20, NextY: 0},
+		{Name: "bottom-end", Edge: bamenn.SlideFromBottom, Parallax: 1, Frames: 4, PrevX: 0, PrevY: -30, NextX: 0, NextY: 0},
+		{Name: "top-half", Edge: bamenn.SlideFromTop, Parallax: 1, Frames: 2, PrevX: 0, PrevY: 15, NextX: 0, NextY: -15},
+		{Name: "parallax", Edge: bamenn.SlideFromRight, Parallax: 0.5, Frames: 2, PrevX: -10, PrevY: 0, NextX: 20, NextY: 0},
+		{Name: "cover", Edge: bamenn.SlideFromRight, Parallax: 0, Frames: 2, PrevX: 0, PrevY: 0, NextX: 20, NextY: 0},
+		{Name: "over", Edge: bamenn.SlideFromRight, Parallax: 1, Frames: 10, PrevX: -40, PrevY: 0, NextX: 0, NextY: 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tran := bamenn.NewSlideTransition(4, c.Edge)
+			tran.SetParallax(c.Parallax)
+			tran.Reset()
+			for range c.Frames {
+				tran.Update()
+			}
+
+			prev, next := tran.SceneGeoMs(40, 30)
+			if x, y := prev.Apply(0, 0); x != c.PrevX || y != c.PrevY {
+				t.Errorf("previous: expected (%v, %v), but got (%v, %v)", c.PrevX, c.PrevY, x, y)
+			}
+			if x, y := next.Apply(0, 0); x != c.NextX || y != c.NextY {
+				t.Errorf("next: expected (%v, %v), but got (%v, %v)", c.NextX, c.NextY, x, y)
+			}
+		})
+	}
+}
+
+func TestSlideTransitionEasing(t *testing.T) {
+	tran := bamenn.NewSlideTransition(4, bamenn.SlideFromRight)
+	tran.SetEasing(bamenn.EaseInQuad)
+	tran.Reset()
+	tran.Update()
+	tran.Update()
+
+	if r := tran.Rate(); r != 0.25 {
+		t.Errorf("expected rate 0.25, but got %v", r)
+	}
+	if tran.Completed() {
+		t.Errorf("must not be completed")
+	}
+	tran.Update()
+	tran.Update()
+	if !tran.Completed() {
+		t.Errorf("must be completed")
+	}
+}
+
+func TestSequenceSlideTransition(t *testing.T) {
+	r := recorder{}
+
+	s1 := &gameForTest{Name: "s1", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	s2 := &gameForTest{Name: "s2", Recorder: &r, UpdateFn: func() error { return nil }, LayoutW: 3, LayoutH: 3}
+	tran := bamenn.NewSlideTransition(3, bamenn.SlideFromRight)
+
+	seq := bamenn.NewSequence(s1)
+	screen := ebiten.NewImage(3, 3)
+
+	for i := range 5 {
+		seq.Layout(3, 3)
+		if i == 1 {
+			seq.SwitchWithTransition(s2, tran)
+		}
+		if err := seq.Update(); err != nil {
+			t.Fatalf("unexpected err on Update(): %v", err)
+		}
+		seq.Draw(screen)
+	}
+
+	compareLogs(t, []string{
+		"s1:layout",
+		"s1:update",
+		"s1:draw",
+		"s1:layout",
+		"s1:draw", // captured
+		"s2:update",
+		"s2:draw",
+		"s2:layout",
+		"s2:update",
+		"s2:draw",
+		"s2:layout",
+		"s2:update",
+		"s2:draw",
+		"s2:layout",
+		"s2:update",
+		"s2:draw",
+	}, r.Log)
+}
@@ -0,0 +1,200 @@
+package bamenn
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn/internal/gameutil"
+)
+
+// ViewportLayout returns n viewport rectangles in bounds. The i-th rectangle is assigned to the i-th Game.
+// Games without a rectangle get empty viewports.
+type ViewportLayout func(bounds image.Rectangle, n int) []image.Rectangle
+
+var (
+	// ViewportsSideBySide splits the screen into columns of the same width.
+	ViewportsSideBySide ViewportLayout = func(bounds image.Rectangle, n int) []image.Rectangle {
+		return ViewportsGrid(n)(bounds, n)
+	}
+
+	// ViewportsStacked splits the screen into rows of the same height.
+	ViewportsStacked ViewportLayout = func(bounds image.Rectangle, n int) []image.Rectangle {
+		return ViewportsGrid(1)(bounds, n)
+	}
+)
+
+// ViewportsGrid returns a ViewportLayout that splits the screen into a grid with the number of columns.
+// If columns is less than 1, the number of columns is chosen to make the grid as square as possible.
+func ViewportsGrid(columns int) ViewportLayout {
+	return func(bounds image.Rectangle, n int) []image.Rectangle {
+		if n <= 0 {
+			return nil
+		}
+
+		cols := columns
+		if cols < 1 {
+			cols = int(math.Ceil(math.Sqrt(float64(n))))
+		}
+		cols = min(cols, n)
+		rows := (n + cols - 1) / cols
+
+		w, h := bounds.Dx(), bounds.Dy()
+		rects := make([]image.Rectangle, n)
+		for i := range rects {
+			c, r := i%cols, i/cols
+			rects[i] = image.Rect(
+				bounds.Min.X+c*w/cols,
+				bounds.Min.Y+r*h/rows,
+				bounds.Min.X+(c+1)*w/cols,
+				bounds.Min.Y+(r+1)*h/rows,
+			)
+		}
+		return rects
+	}
+}
+
+// ViewportsFixed returns a ViewportLayout that assigns the rectangles as they are.
+// Games beyond the number of the rectangles get empty viewports.
+func ViewportsFixed(rects ...image.Rectangle) ViewportLayout {
+	rects = slices.Clone(rects)
+	return func(bounds image.Rectangle, n int) []image.Rectangle {
+		vs := make([]image.Rectangle, n)
+		copy(vs, rects)
+		return vs
+	}
+}
+
+// SplitScreen draws each Game in its own viewport, e.g. for local multiplayer. Update and the event functions are processed as Parallel.
+// Layout of each Game is called with the size of its viewport, and the Game is drawn into the viewport.
+// Viewports are recalculated in Layout, so they adapt when Games are added or removed.
+// The i-th Game in the order of Add gets the i-th viewport.
+type SplitScreen struct {
+	parallelGames
+	viewportLayout ViewportLayout
+	scaling        ScalingPolicy
+	subImage       bool
+	viewports      []image.Rectangle
+	sizes          []image.Point
+	buffers        []gameutil.Buffer
+}
+
+// NewSplitScreen creates a new SplitScreen instance. The ViewportLayout is ViewportsSideBySide by default.
+func NewSplitScreen(games ...ebiten.Game) *SplitScreen {
+	return &SplitScreen{
+		parallelGames:  parallelGames{parallel: NewParallel(games...)},
+		viewportLayout: ViewportsSideBySide,
+		scaling:        ScalingLetterbox,
+	}
+}
+
+// Add adds the Game after the other Games. It gets the next viewport from the next Layout.
+// Event functions of the Game are not called by Add.
+func (s *SplitScreen) Add(game ebiten.Game) {
+	s.parallel.Add(game)
+}
+
+// Remove removes the Game. It returns false if the Game is not held.
+// Event functions of the Game are not called by Remove.
+func (s *SplitScreen) Remove(game ebiten.Game) bool {
+	return s.parallel.Remove(game)
+}
+
+// SetViewportLayout sets the ViewportLayout to assign viewports to the Games.
+func (s *SplitScreen) SetViewportLayout(layout ViewportLayout) {
+	s.viewportLayout = layout
+}
+
+// SetScalingPolicy sets how a Game is drawn when its Layout differs from the size of its viewport. The default is ScalingLetterbox.
+func (s *SplitScreen) SetScalingPolicy(policy ScalingPolicy) {
+	s.scaling = policy
+}
+
+// SetSubImageDrawing sets whether a Game whose Layout equals the size of its viewport is drawn into a SubImage of the screen directly.
+// It is cheaper than drawing into an offscreen image, but the Game must draw relative to screen.Bounds().Min because a SubImage keeps the coordinates of the screen.
+// It is false by default.
+func (s *SplitScreen) SetSubImageDrawing(enabled bool) {
+	s.subImage = enabled
+}
+
+// Viewport returns the viewport of the Game calculated by the last Layout. It returns false if the Game is not held.
+func (s *SplitScreen) Viewport(game ebiten.Game) (image.Rectangle, bool) {
+	i := s.parallel.index(game)
+	if i < 0 || i >= len(s.viewports) {
+		return image.Rectangle{}, false
+	}
+	return s.viewports[i], true
+}
+
+// LayoutSize returns the size returned by Layout or LayoutF of the Game at the last Layout or LayoutF of it.
+// It returns false if the Game has not been laid out.
+func (s *SplitScreen) LayoutSize(game ebiten.Game) (LayoutSize, bool) {
+	i := s.parallel.index(game)
+	if i < 0 || i >= len(s.sizes) {
+		return LayoutSize{}, false
+	}
+	return LayoutSize{Width: float64(s.sizes[i].X), Height: float64(s.sizes[i].Y)}, true
+}
+
+// Layout is ebiten.Game implementation. The screen size is the outside size.
+func (s *SplitScreen) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	s.layoutViewports(float64(outsideWidth), float64(outsideHeight))
+	return outsideWidth, outsideHeight
+}
+
+// LayoutF is ebiten.LayoutFer implementation. The screen size is the outside size.
+func (s *SplitScreen) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	s.layoutViewports(outsideWidth, outsideHeight)
+	return outsideWidth, outsideHeight
+}
+
+// layoutViewports assigns viewports to the Games and calls their Layouts with the viewport sizes.
+func (s *SplitScreen) layoutViewports(outsideWidth, outsideHeight float64) {
+	games := s.Games()
+	bounds := image.Rect(0, 0, int(math.Ceil(outsideWidth)), int(math.Ceil(outsideHeight)))
+	s.viewports = slices.Grow(s.viewports[:0], len(games))[:len(games)]
+	clear(s.viewports)
+	copy(s.viewports, s.viewportLayout(bounds, len(games)))
+	s.sizes = slices.Grow(s.sizes[:0], len(games))[:len(games)]
+
+	for i, g := range games {
+		v := s.viewports[i]
+		w, h := sceneSize(g, float64(v.Dx()), float64(v.Dy()))
+		s.sizes[i] = image.Pt(w, h)
+		callIfImpl(g, func(c ScreenSizeSetter) { c.SetScreenSize(float64(v.Dx()), float64(v.Dy())) })
+	}
+}
+
+// Draw is ebiten.Game implementation.
+// It draws each Game into an offscreen image of its Layout size, and draws the image into its viewport according to the ScalingPolicy.
+func (s *SplitScreen) Draw(screen *ebiten.Image) {
+	games := s.Games()
+	if len(s.viewports) != len(games) {
+		s.layoutViewports(float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy()))
+	}
+	if len(s.buffers) < len(games) {
+		s.buffers = append(s.buffers, make([]gameutil.Buffer, len(games)-len(s.buffers))...)
+	}
+	for i := len(games); i < len(s.buffers); i++ {
+		s.buffers[i].Deallocate()
+	}
+	s.buffers = s.buffers[:len(games)]
+
+	origin := screen.Bounds().Min
+	for i, g := range games {
+		v := s.viewports[i].Add(origin).Intersect(screen.Bounds())
+		if v.Empty() {
+			continue
+		}
+		dst := screen.SubImage(v).(*ebiten.Image)
+
+		if size := s.sizes[i]; s.subImage && size == v.Size() {
+			g.Draw(dst)
+			continue
+		}
+		img := s.buffers[i].Get(s.sizes[i].X, s.sizes[i].Y)
+		g.Draw(img)
+		s.scaling.drawScaled(dst, img)
+	}
+}
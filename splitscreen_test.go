@@ -0,0 +1,143 @@
+package bamenn_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/noppikinatta/bamenn"
+)
+
+func TestViewportLayout(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 60)
+
+	cases := []struct {
+		Name     string
+		Layout   bamenn.ViewportLayout
+		N        int
+		Expected []image.Rectangle
+	}{
+		{
+			Name:     "side-by-side",
+			Layout:   bamenn.ViewportsSideBySide,
+			N:        3,
+			Expected: []image.Rectangle{image.Rect(0, 0, 33, 60), image.Rect(33, 0, 66, 60), image.Rect(66, 0, 100, 60)},
+		},
+		{
+			Name:     "stacked",
+			Layout:   bamenn.ViewportsStacked,
+			N:        2,
+			Expected: []image.Rectangle{image.Rect(0, 0, 100, 30), image.Rect(0, 30, 100, 60)},
+		},
+		{
+			Name:     "grid-auto",
+			Layout:   bamenn.ViewportsGrid(0),
+			N:        3,
+			Expected: []image.Rectangle{image.Rect(0, 0, 50, 30), image.Rect(50, 0, 100, 30), image.Rect(0, 30, 50, 60)},
+		},
+		{
+			Name:     "fixed",
+			Layout:   bamenn.ViewportsFixed(image.Rect(0, 0, 100, 60), image.Rect(70, 5, 95, 20)),
+			N:        3,
+			Expected: []image.Rectangle{image.Rect(0, 0, 100, 60), image.Rect(70, 5, 95, 20), {}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			rects := c.Layout(bounds, c.N)
+			if len(rects) != len(c.Expected) {
+				t.Fatalf("expected %d rects, but got %d", len(c.Expected), len(rects))
+			}
+			for i := range rects {
+				if rects[i] != c.Expected[i] {
+					t.Errorf("%d: expected %v, but got %v", i, c.Expected[i], rects[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitScreen(t *testing.T) {
+	p1 := &viewportGameForTest{}
+	p2 := &viewportGameForTest{}
+	s := bamenn.NewSplitScreen(p1, p2)
+
+	w, h := s.LayoutF(200, 100)
+	if w != 200 || h != 100 {
+		t.Errorf("expected (200, 100), but got (%f, %f)", w, h)
+	}
+	if p1.outsideWidth != 100 || p1.outsideHeight != 100 {
+		t.Errorf("expected (100, 100), but got (%f, %f)", p1.outsideWidth, p1.outsideHeight)
+	}
+	if v, _ := s.Viewport(p2); v != image.Rect(100, 0, 200, 100) {
+		t.Errorf("unexpected viewport: %v", v)
+	}
+
+	p3 := &viewportGameForTest{}
+	s.Add(p3)
+	s.SetViewportLayout(bamenn.ViewportsStacked)
+	s.LayoutF(200, 90)
+	for i, g := range []*viewportGameForTest{p1, p2, p3} {
+		if g.outsideWidth != 200 || g.outsideHeight != 30 {
+			t.Errorf("%d: expected (200, 30), but got (%f, %f)", i, g.outsideWidth, g.outsideHeight)
+		}
+	}
+
+	screen := ebiten.NewImage(200, 90)
+	s.Draw(screen)
+	for i, g := range []*viewportGameForTest{p1, p2, p3} {
+		if g.drawnSize != image.Pt(200, 30) {
+			t.Errorf("%d: expected drawn size (200, 30), but got %v", i, g.drawnSize)
+		}
+	}
+
+	if size, ok := s.LayoutSize(p3); !ok || size != (bamenn.LayoutSize{Width: 200, Height: 30}) {
+		t.Errorf("expected layout size (200, 30), but got %v", size)
+	}
+
+	s.Remove(p2)
+	s.LayoutF(200, 90)
+	if v, _ := s.Viewport(p3); v != image.Rect(0, 45, 200, 90) {
+		t.Errorf("unexpected viewport after Remove(): %v", v)
+	}
+
+	// A parent Parallel must not overwrite the screen sizes of the viewports.
+	if _, ok := any(s).(bamenn.ScreenSizeSetter); ok {
+		t.Errorf("SplitScreen should not implement ScreenSizeSetter")
+	}
+}
+
+func TestSplitScreenFewerViewports(t *testing.T) {
+	p1 := &viewportGameForTest{}
+	p2 := &viewportGameForTest{}
+	s := bamenn.NewSplitScreen(p1, p2)
+	s.LayoutF(200, 100)
+
+	// The custom layout returns only one rectangle, so p2 gets an empty viewport instead of the previous one.
+	s.SetViewportLayout(func(bounds image.Rectangle, n int) []image.Rectangle {
+		return []image.Rectangle{bounds}
+	})
+	s.LayoutF(200, 100)
+	if v, _ := s.Viewport(p1); v != image.Rect(0, 0, 200, 100) {
+		t.Errorf("unexpected viewport: %v", v)
+	}
+	if v, _ := s.Viewport(p2); !v.Empty() {
+		t.Errorf("viewport should be empty, but got %v", v)
+	}
+}
+
+type viewportGameForTest struct {
+	gameForTest
+	outsideWidth, outsideHeight float64
+	drawnSize                   image.Point
+}
+
+func (v *viewportGameForTest) LayoutF(outsideWidth, outsideHeight float64) (float64, float64) {
+	v.outsideWidth, v.outsideHeight = outsideWidth, outsideHeight
+	return outsideWidth, outsideHeight
+}
+
+func (v *viewportGameForTest) Draw(screen *ebiten.Image) {
+	v.drawnSize = screen.Bounds().Size()
+}
@@ -64,3 +64,10 @@ func defaultDrawFinalScreenTemporaryImplRemoveItWhenEbitengineV290Released(scree
 		screen.DrawRectShader(w, h, theScreenShader, op)
 	}
 }
+
+// DefaultDrawFinalScreen draws offscreen onto screen in the same way as the default of Ebitengine.
+// It is useful for ebiten.FinalScreenDrawer implementations that only modify geoM.
+// TODO: replace to ebiten.DefaultDrawFinalScreen when Ebitengine v2.9.0 is released
+func DefaultDrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	defaultDrawFinalScreenTemporaryImplRemoveItWhenEbitengineV290Released(screen, offscreen, geoM)
+}
@@ -22,6 +22,56 @@ type Transition interface {
 	CanSwitchScenes() bool
 }
 
+// FreezeFrameSetter is an interface for a Transition that animates the still image of the previous scene.
+// It is used when the freeze frame of Sequence is enabled by Sequence.SetFreezeFrame.
+type FreezeFrameSetter interface {
+	// SetFreezeFrame is called with the last frame of the previous scene when the Transition starts, and with nil when it completes.
+	// The image must not be modified.
+	SetFreezeFrame(img *ebiten.Image)
+}
+
+// SceneCompositor is an interface for a Transition that places the previous and next scenes by itself, e.g. SlideTransition.
+// While it is processed, Sequence captures the last frame of the previous scene as the freeze frame,
+// and draws it and the next scene with the GeoMs instead of drawing the current scene as it is.
+// The next scene is drawn only after scenes are switched.
+type SceneCompositor interface {
+	// SceneGeoMs returns the GeoMs to draw the previous and next scenes onto the screen of the size.
+	SceneGeoMs(width, height float64) (previous, next ebiten.GeoM)
+}
+
+// SceneUpdateHolder is an interface for a Transition that holds back Update of the next scene, e.g. InterstitialTransition.
+// While it is held, Sequence does not update the current scene and routes input to the Transition if it implements InputHandler.
+type SceneUpdateHolder interface {
+	// HoldsSceneUpdate returns true while the current scene of Sequence must not be updated.
+	HoldsSceneUpdate() bool
+}
+
+// TransitionPhase represents the phase of a Transition in Sequence.
+type TransitionPhase int
+
+const (
+	// TransitionPhaseNone means that no Transition is being processed.
+	TransitionPhaseNone TransitionPhase = iota
+	// TransitionPhaseBeforeSwitch means that the Transition is being processed and scenes are not switched yet.
+	TransitionPhaseBeforeSwitch
+	// TransitionPhaseAfterSwitch means that the Transition is being processed and scenes are already switched.
+	TransitionPhaseAfterSwitch
+)
+
+// String returns the name of the phase.
+func (p TransitionPhase) String() string {
+	switch p {
+	case TransitionPhaseNone:
+		return "none"
+	case TransitionPhaseBeforeSwitch:
+		return "before switch"
+	case TransitionPhaseAfterSwitch:
+		return "after switch"
+	default:
+		return "unknown"
+	}
+}
+
 // NopTransition is a Transition that does not draw anything.
 var NopTransition Transition = nopTransition{}
 
@@ -38,6 +88,7 @@ type LinearTransition struct {
 	currentFrame  int
 	frameToSwitch int
 	maxFrames     int
+	easing        Easing
 	drawer        LinearTransitionDrawer
 }
 
@@ -46,6 +97,11 @@ func NewLinearTransition(frameToSwitch, maxFrames int, drawer LinearTransitionDr
 	return &LinearTransition{frameToSwitch: frameToSwitch, maxFrames: maxFrames, drawer: drawer}
 }
 
+// SetEasing sets the Easing passed to LinearTransitionDrawer with LinearTransitionProgress. The default is linear.
+func (t *LinearTransition) SetEasing(easing Easing) {
+	t.easing = easing
+}
+
 // LinearTransitionDrawer is an interface that draws as the LinearTransition progresses.
 type LinearTransitionDrawer interface {
 	// Draw draws as the LinearTransition progresses.
@@ -54,9 +110,10 @@ type LinearTransitionDrawer interface {
 
 // LinearTransitionProgress represents the progress of LinearTransition.
 type LinearTransitionProgress struct {
-	CurrentFrame  int // CurrentFrame is the current frame.
-	MaxFrames     int // MaxFrames is the maximum number of frames.
-	FrameToSwitch int // FrameToSwitch returns the frame to switch scenes.
+	CurrentFrame  int    // CurrentFrame is the current frame.
+	MaxFrames     int    // MaxFrames is the maximum number of frames.
+	FrameToSwitch int    // FrameToSwitch returns the frame to switch scenes.
+	Easing        Easing // Easing is the Easing set to LinearTransition. It may be nil.
 }
 
 // Rate returns the progress rate of LinearTransition in the range of 0.0~1.0.
@@ -64,6 +121,11 @@ func (p LinearTransitionProgress) Rate() float64 {
 	return float64(p.CurrentFrame) / float64(p.MaxFrames)
 }
 
+// EasedRate returns Rate eased by the Easing.
+func (p LinearTransitionProgress) EasedRate() float64 {
+	return p.Easing.Apply(p.Rate())
+}
+
 // Reset is called at the start of a scene transition.
 // If the same instance is to be used multiple times, Reset should be used to initialize the Transition state.
 func (t *LinearTransition) Reset() {
@@ -86,12 +148,21 @@ func (t *LinearTransition) Draw(screen *ebiten.Image) {
 	t.drawer.Draw(screen, p)
 }
 
+// SetFreezeFrame is FreezeFrameSetter implementation.
+// It passes the image to the LinearTransitionDrawer if it implements FreezeFrameSetter.
+func (t *LinearTransition) SetFreezeFrame(img *ebiten.Image) {
+	if f, ok := t.drawer.(FreezeFrameSetter); ok {
+		f.SetFreezeFrame(img)
+	}
+}
+
 // Progress returns the progress of it.
 func (t *LinearTransition) Progress() LinearTransitionProgress {
 	return LinearTransitionProgress{
 		FrameToSwitch: t.frameToSwitch,
 		CurrentFrame:  t.currentFrame,
 		MaxFrames:     t.maxFrames,
+		Easing:        t.easing,
 	}
 }
 
@@ -108,10 +179,12 @@ func (t *LinearTransition) CanSwitchScenes() bool {
 }
 
 type transitionUpdater struct {
-	seq        *Sequence
-	next       ebiten.Game
-	transition Transition
-	switched   bool
+	seq          *Sequence
+	next         ebiten.Game
+	transition   Transition
+	switched     bool
+	outputWidth  float64
+	outputHeight float64
 }
 
 func newTransitionUpdater(seq *Sequence, next ebiten.Game, transition Transition) *transitionUpdater {